@@ -7,6 +7,8 @@ package request
 
 import (
 	"time"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/secret"
 )
 
 // Reference doc about the Go tags:
@@ -21,21 +23,21 @@ import (
 
 // TokenSearch holds values used in verbatim searches for tokens.
 type TokenSearch struct {
-	Token    string `json:"token" format:"password"`
-	UserName string `json:"userName"`
+	Token    secret.Secret `json:"token" format:"password"`
+	UserName string        `json:"userName"`
 }
 
 // Token specifies fields when creating a new token.
 type Token struct {
-	Token      string `json:"token" format:"password" validate:"required"`
-	UserName   string `json:"userName" validate:"required"`
-	ProviderID uint   `json:"providerId" minimum:"0"`
+	Token      secret.Secret `json:"token" format:"password" validate:"required"`
+	UserName   string        `json:"userName" validate:"required"`
+	ProviderID uint          `json:"providerId" minimum:"0"`
 }
 
 // TokenUpdate specifies fields when updating a token.
 type TokenUpdate struct {
-	Token    string `json:"token" format:"password" validate:"required"`
-	UserName string `json:"userName" validate:"required"`
+	Token    secret.Secret `json:"token" format:"password" validate:"required"`
+	UserName string        `json:"userName" validate:"required"`
 }
 
 // Branch specifies fields when adding a new branch to a project.
@@ -7,6 +7,7 @@ package response
 import (
 	"time"
 
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/secret"
 	"gopkg.in/guregu/null.v4"
 )
 
@@ -339,6 +340,23 @@ const (
 	TestStatusNoTests TestStatus = "No tests"
 )
 
+// TestResultDetailJSONFields holds the JSON field names for each field.
+// Useful in ordering statements to map the correct field to the correct
+// database column.
+var TestResultDetailJSONFields = struct {
+	TestResultDetailID string
+	Name               string
+	StartedOn          string
+	CompletedOn        string
+	Status             string
+}{
+	TestResultDetailID: "testResultDetailId",
+	Name:               "name",
+	StartedOn:          "startedOn",
+	CompletedOn:        "completedOn",
+	Status:             "status",
+}
+
 // TestResultDetail contains data about a single test in a test result file.
 type TestResultDetail struct {
 	TimeMetadata
@@ -412,7 +430,7 @@ var TokenJSONFields = struct {
 // Token holds credentials for a remote provider.
 type Token struct {
 	TimeMetadata
-	TokenID  uint   `json:"tokenId" minimum:"0"`
-	Token    string `json:"token" format:"password"`
-	UserName string `json:"userName"`
+	TokenID  uint          `json:"tokenId" minimum:"0"`
+	Token    secret.Secret `json:"token" format:"password"`
+	UserName string        `json:"userName"`
 }
@@ -0,0 +1,52 @@
+// Package secret contains the Secret type, used for sensitive string values
+// such as tokens, that should never be printed by accident.
+package secret
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// Redacted is the text used in place of a secret's value.
+const Redacted = "*REDACTED*"
+
+// Secret is a string value that is redacted when formatted using the fmt
+// package, such as via fmt.Sprint, fmt.Printf, or when logged, but is encoded
+// as-is by encoding/json so it can still be sent to the API.
+//
+// Use Reveal to get the actual value.
+type Secret string
+
+// Reveal returns the actual value of the secret.
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+// String returns a redacted text, or an empty string if the secret is empty.
+// Implements fmt.Stringer.
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return Redacted
+}
+
+// GoString returns a redacted Go syntax representation of the secret.
+// Implements fmt.GoStringer, used by the %#v formatting verb.
+func (s Secret) GoString() string {
+	return "secret.Secret(" + strconv.Quote(s.String()) + ")"
+}
+
+// Format writes the redacted text regardless of formatting verb.
+// Implements fmt.Formatter.
+func (s Secret) Format(f fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && f.Flag('#'):
+		io.WriteString(f, s.GoString())
+	case verb == 'q':
+		io.WriteString(f, strconv.Quote(s.String()))
+	default:
+		io.WriteString(f, s.String())
+	}
+}
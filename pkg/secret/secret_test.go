@@ -0,0 +1,51 @@
+package secret
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSecret_formatting(t *testing.T) {
+	s := Secret("hunter2")
+	type wrapper struct {
+		Token Secret
+	}
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"Sprint", fmt.Sprint(s), Redacted},
+		{"%s", fmt.Sprintf("%s", s), Redacted},
+		{"%v", fmt.Sprintf("%v", s), Redacted},
+		{"%q", fmt.Sprintf("%q", s), `"*REDACTED*"`},
+		{"%x", fmt.Sprintf("%x", s), Redacted},
+		{"%#v", fmt.Sprintf("%#v", s), `secret.Secret("*REDACTED*")`},
+		{"struct %+v", fmt.Sprintf("%+v", wrapper{s}), "{Token:*REDACTED*}"},
+		{"pointer", fmt.Sprint(&s), Redacted},
+		{"empty", fmt.Sprint(Secret("")), ""},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tc.got)
+		})
+	}
+}
+
+func TestSecret_JSON(t *testing.T) {
+	body, err := json.Marshal(struct {
+		Token Secret `json:"token"`
+	}{"hunter2"})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"token":"hunter2"}`, string(body))
+
+	var decoded struct {
+		Token Secret `json:"token"`
+	}
+	require.NoError(t, json.Unmarshal(body, &decoded))
+	assert.Equal(t, "hunter2", decoded.Token.Reveal())
+}
@@ -0,0 +1,337 @@
+package testresult
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+)
+
+// Default values used in CompareOptions.
+const (
+	DefaultDurationChangeThreshold = time.Second
+	DefaultDurationChangeRatio     = 0.5
+)
+
+// CompareOptions holds optional settings used when comparing test results.
+type CompareOptions struct {
+	// DurationChangeThreshold is the minimum absolute change in duration for
+	// a test to be listed as a duration change. Defaults to 1 second.
+	DurationChangeThreshold time.Duration
+	// DurationChangeRatio is the minimum relative change in duration, where
+	// 0.5 means 50% slower or faster, for a test to be listed as a duration
+	// change. Defaults to 0.5.
+	DurationChangeRatio float64
+}
+
+// TestChange is a single test that differs between two test runs. The Base
+// fields are zero for added tests, and the Head fields are zero for removed
+// tests.
+type TestChange struct {
+	Name         string
+	BaseStatus   response.TestResultStatus
+	HeadStatus   response.TestResultStatus
+	BaseDuration time.Duration
+	HeadDuration time.Duration
+	// Message is the test's message from the head test run, or from the base
+	// test run if the test was removed.
+	Message string
+}
+
+// DurationChange returns the difference in duration between the base and head
+// test run, where a positive value means the test got slower.
+func (c TestChange) DurationChange() time.Duration {
+	return c.HeadDuration - c.BaseDuration
+}
+
+// Diff is the difference between a base and a head test run, such as between
+// two builds.
+type Diff struct {
+	BaseBuildID uint
+	HeadBuildID uint
+
+	NewlyFailing    []TestChange
+	NewlyPassing    []TestChange
+	NewlySkipped    []TestChange
+	Added           []TestChange
+	Removed         []TestChange
+	DurationChanges []TestChange
+}
+
+// HasRegressions returns true if any test started failing, or if any added
+// test is failing.
+func (d Diff) HasRegressions() bool {
+	if len(d.NewlyFailing) > 0 {
+		return true
+	}
+	for _, c := range d.Added {
+		if c.HeadStatus == response.TestResultStatusFailed {
+			return true
+		}
+	}
+	return false
+}
+
+// IsEmpty returns true if no differences were found.
+func (d Diff) IsEmpty() bool {
+	return len(d.NewlyFailing) == 0 &&
+		len(d.NewlyPassing) == 0 &&
+		len(d.NewlySkipped) == 0 &&
+		len(d.Added) == 0 &&
+		len(d.Removed) == 0 &&
+		len(d.DurationChanges) == 0
+}
+
+type testAggregate struct {
+	status   response.TestResultStatus
+	duration time.Duration
+	message  string
+}
+
+// aggregateByName merges test details by name, as some test runners report
+// parameterized tests multiple times. The merged status is Failed if any of
+// them failed, otherwise Success if any of them passed, and otherwise Skipped.
+func aggregateByName(details []response.TestResultDetail) (map[string]testAggregate, []string) {
+	tests := make(map[string]testAggregate, len(details))
+	var names []string
+	for _, d := range details {
+		agg, ok := tests[d.Name]
+		if !ok {
+			names = append(names, d.Name)
+		}
+		if statusPriority(d.Status) > statusPriority(agg.status) {
+			agg.status = d.Status
+			if d.Message.Valid {
+				agg.message = d.Message.String
+			}
+		}
+		agg.duration += DetailDuration(d)
+		tests[d.Name] = agg
+	}
+	return tests, names
+}
+
+func statusPriority(status response.TestResultStatus) int {
+	switch status {
+	case response.TestResultStatusFailed:
+		return 3
+	case response.TestResultStatusSuccess:
+		return 2
+	case response.TestResultStatusSkipped:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// DetailDuration returns the duration of a test, or zero if unknown.
+func DetailDuration(d response.TestResultDetail) time.Duration {
+	if !d.StartedOn.Valid || !d.CompletedOn.Valid {
+		return 0
+	}
+	return d.CompletedOn.Time.Sub(d.StartedOn.Time)
+}
+
+// Compare matches the test details from a base and head test run by name, and
+// returns the differences between them.
+func Compare(base, head []response.TestResultDetail, opts CompareOptions) Diff {
+	if opts.DurationChangeThreshold <= 0 {
+		opts.DurationChangeThreshold = DefaultDurationChangeThreshold
+	}
+	if opts.DurationChangeRatio <= 0 {
+		opts.DurationChangeRatio = DefaultDurationChangeRatio
+	}
+	baseTests, baseNames := aggregateByName(base)
+	headTests, headNames := aggregateByName(head)
+
+	var diff Diff
+	for _, name := range headNames {
+		h := headTests[name]
+		b, ok := baseTests[name]
+		if !ok {
+			diff.Added = append(diff.Added, TestChange{
+				Name:         name,
+				HeadStatus:   h.status,
+				HeadDuration: h.duration,
+				Message:      h.message,
+			})
+			continue
+		}
+		change := TestChange{
+			Name:         name,
+			BaseStatus:   b.status,
+			HeadStatus:   h.status,
+			BaseDuration: b.duration,
+			HeadDuration: h.duration,
+			Message:      h.message,
+		}
+		if b.status != h.status {
+			switch h.status {
+			case response.TestResultStatusFailed:
+				diff.NewlyFailing = append(diff.NewlyFailing, change)
+			case response.TestResultStatusSuccess:
+				diff.NewlyPassing = append(diff.NewlyPassing, change)
+			case response.TestResultStatusSkipped:
+				diff.NewlySkipped = append(diff.NewlySkipped, change)
+			}
+		}
+		if isSignificantDurationChange(b.duration, h.duration, opts) {
+			diff.DurationChanges = append(diff.DurationChanges, change)
+		}
+	}
+	for _, name := range baseNames {
+		if _, ok := headTests[name]; ok {
+			continue
+		}
+		b := baseTests[name]
+		diff.Removed = append(diff.Removed, TestChange{
+			Name:         name,
+			BaseStatus:   b.status,
+			BaseDuration: b.duration,
+			Message:      b.message,
+		})
+	}
+	sort.SliceStable(diff.DurationChanges, func(i, j int) bool {
+		return absDuration(diff.DurationChanges[i].DurationChange()) >
+			absDuration(diff.DurationChanges[j].DurationChange())
+	})
+	return diff
+}
+
+func isSignificantDurationChange(base, head time.Duration, opts CompareOptions) bool {
+	if base <= 0 || head <= 0 {
+		return false
+	}
+	delta := absDuration(head - base)
+	if delta < opts.DurationChangeThreshold {
+		return false
+	}
+	return float64(delta)/float64(base) >= opts.DurationChangeRatio
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// WriteMarkdown writes the diff as Markdown, suitable for pull request
+// comments.
+func (d Diff) WriteMarkdown(w io.Writer) error {
+	ew := &errWriter{w: w}
+	ew.printf("## Test results: build #%d compared to build #%d\n\n", d.HeadBuildID, d.BaseBuildID)
+	if d.IsEmpty() {
+		ew.printf("No changes in test results.\n")
+		return ew.err
+	}
+	writeMarkdownChangeSection(ew, "Newly failing", d.NewlyFailing, true)
+	writeMarkdownChangeSection(ew, "Newly passing", d.NewlyPassing, false)
+	writeMarkdownChangeSection(ew, "Newly skipped", d.NewlySkipped, false)
+	writeMarkdownChangeSection(ew, "Added", d.Added, true)
+	writeMarkdownChangeSection(ew, "Removed", d.Removed, false)
+	if len(d.DurationChanges) > 0 {
+		ew.printf("### Duration changes (%d)\n\n", len(d.DurationChanges))
+		ew.printf("| Test | Before | After | Change |\n")
+		ew.printf("| --- | ---: | ---: | ---: |\n")
+		for _, c := range d.DurationChanges {
+			ew.printf("| %s | %s | %s | %s |\n", escapeMarkdownTable(c.Name),
+				c.BaseDuration, c.HeadDuration, formatDurationChange(c.DurationChange()))
+		}
+		ew.printf("\n")
+	}
+	return ew.err
+}
+
+func writeMarkdownChangeSection(ew *errWriter, title string, changes []TestChange, withMessage bool) {
+	if len(changes) == 0 {
+		return
+	}
+	ew.printf("### %s (%d)\n\n", title, len(changes))
+	ew.printf("| Test | Before | After |\n")
+	ew.printf("| --- | --- | --- |\n")
+	for _, c := range changes {
+		ew.printf("| %s | %s | %s |\n", escapeMarkdownTable(c.Name),
+			formatStatus(c.BaseStatus), formatStatus(c.HeadStatus))
+	}
+	ew.printf("\n")
+	if !withMessage {
+		return
+	}
+	for _, c := range changes {
+		if c.HeadStatus != response.TestResultStatusFailed || c.Message == "" {
+			continue
+		}
+		ew.printf("<details><summary>%s</summary>\n\n```\n%s\n```\n\n</details>\n\n",
+			template.HTMLEscapeString(c.Name), strings.ReplaceAll(c.Message, "```", "` ` `"))
+	}
+}
+
+// WriteText writes the diff as plain text, suitable for console output.
+func (d Diff) WriteText(w io.Writer) error {
+	ew := &errWriter{w: w}
+	ew.printf("Test results: build #%d compared to build #%d\n", d.HeadBuildID, d.BaseBuildID)
+	if d.IsEmpty() {
+		ew.printf("No changes in test results.\n")
+		return ew.err
+	}
+	writeTextChangeSection(ew, "Newly failing", d.NewlyFailing)
+	writeTextChangeSection(ew, "Newly passing", d.NewlyPassing)
+	writeTextChangeSection(ew, "Newly skipped", d.NewlySkipped)
+	writeTextChangeSection(ew, "Added", d.Added)
+	writeTextChangeSection(ew, "Removed", d.Removed)
+	if len(d.DurationChanges) > 0 {
+		ew.printf("\nDuration changes (%d):\n", len(d.DurationChanges))
+		for _, c := range d.DurationChanges {
+			ew.printf("  %s: %s -> %s (%s)\n", c.Name,
+				c.BaseDuration, c.HeadDuration, formatDurationChange(c.DurationChange()))
+		}
+	}
+	return ew.err
+}
+
+func writeTextChangeSection(ew *errWriter, title string, changes []TestChange) {
+	if len(changes) == 0 {
+		return
+	}
+	ew.printf("\n%s (%d):\n", title, len(changes))
+	for _, c := range changes {
+		ew.printf("  %s: %s -> %s\n", c.Name, formatStatus(c.BaseStatus), formatStatus(c.HeadStatus))
+	}
+}
+
+func formatStatus(status response.TestResultStatus) string {
+	if status == "" {
+		return "-"
+	}
+	return string(status)
+}
+
+func formatDurationChange(d time.Duration) string {
+	if d > 0 {
+		return "+" + d.String()
+	}
+	return d.String()
+}
+
+func escapeMarkdownTable(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
+
+// errWriter keeps the first write error, so that consecutive writes can skip
+// the error checking.
+type errWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (ew *errWriter) printf(format string, args ...interface{}) {
+	if ew.err != nil {
+		return
+	}
+	_, ew.err = fmt.Fprintf(ew.w, format, args...)
+}
@@ -0,0 +1,118 @@
+package testresult
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v4"
+)
+
+func newTestDetail(name string, status response.TestResultStatus, duration time.Duration) response.TestResultDetail {
+	start := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	return response.TestResultDetail{
+		Name:        name,
+		Status:      status,
+		StartedOn:   null.TimeFrom(start),
+		CompletedOn: null.TimeFrom(start.Add(duration)),
+	}
+}
+
+func TestCompare(t *testing.T) {
+	base := []response.TestResultDetail{
+		newTestDetail("Unchanged", response.TestResultStatusSuccess, time.Second),
+		newTestDetail("StartsFailing", response.TestResultStatusSuccess, time.Second),
+		newTestDetail("StartsPassing", response.TestResultStatusFailed, time.Second),
+		newTestDetail("StartsSkipping", response.TestResultStatusSuccess, time.Second),
+		newTestDetail("Removed", response.TestResultStatusSuccess, time.Second),
+		newTestDetail("GetsSlower", response.TestResultStatusSuccess, 2*time.Second),
+		newTestDetail("GetsSlightlySlower", response.TestResultStatusSuccess, 10*time.Second),
+	}
+	failing := newTestDetail("StartsFailing", response.TestResultStatusFailed, time.Second)
+	failing.Message = null.StringFrom("oh no")
+	head := []response.TestResultDetail{
+		newTestDetail("Unchanged", response.TestResultStatusSuccess, time.Second),
+		failing,
+		newTestDetail("StartsPassing", response.TestResultStatusSuccess, time.Second),
+		newTestDetail("StartsSkipping", response.TestResultStatusSkipped, time.Second),
+		newTestDetail("Added", response.TestResultStatusSuccess, time.Second),
+		newTestDetail("GetsSlower", response.TestResultStatusSuccess, 5*time.Second),
+		newTestDetail("GetsSlightlySlower", response.TestResultStatusSuccess, 12*time.Second),
+	}
+
+	diff := Compare(base, head, CompareOptions{})
+	names := func(changes []TestChange) []string {
+		var result []string
+		for _, c := range changes {
+			result = append(result, c.Name)
+		}
+		return result
+	}
+	assert.Equal(t, []string{"StartsFailing"}, names(diff.NewlyFailing))
+	assert.Equal(t, "oh no", diff.NewlyFailing[0].Message)
+	assert.Equal(t, []string{"StartsPassing"}, names(diff.NewlyPassing))
+	assert.Equal(t, []string{"StartsSkipping"}, names(diff.NewlySkipped))
+	assert.Equal(t, []string{"Added"}, names(diff.Added))
+	assert.Equal(t, []string{"Removed"}, names(diff.Removed))
+	assert.Equal(t, []string{"GetsSlower"}, names(diff.DurationChanges))
+	assert.Equal(t, 3*time.Second, diff.DurationChanges[0].DurationChange())
+	assert.True(t, diff.HasRegressions(), "has regressions")
+}
+
+func TestCompare_duplicateNamesFailIfAnyFailed(t *testing.T) {
+	base := []response.TestResultDetail{
+		newTestDetail("Param", response.TestResultStatusSuccess, 0),
+	}
+	head := []response.TestResultDetail{
+		newTestDetail("Param", response.TestResultStatusSuccess, 0),
+		newTestDetail("Param", response.TestResultStatusFailed, 0),
+	}
+	diff := Compare(base, head, CompareOptions{})
+	require.Len(t, diff.NewlyFailing, 1)
+	assert.Empty(t, diff.Added)
+}
+
+func TestDiffWriteMarkdown(t *testing.T) {
+	diff := Diff{
+		BaseBuildID: 1,
+		HeadBuildID: 2,
+		NewlyFailing: []TestChange{{
+			Name:       "Foo|Bar",
+			BaseStatus: response.TestResultStatusSuccess,
+			HeadStatus: response.TestResultStatusFailed,
+			Message:    "oh no",
+		}},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, diff.WriteMarkdown(&buf))
+	want := "## Test results: build #2 compared to build #1\n\n" +
+		"### Newly failing (1)\n\n" +
+		"| Test | Before | After |\n" +
+		"| --- | --- | --- |\n" +
+		"| Foo\\|Bar | Success | Failed |\n\n" +
+		"<details><summary>Foo|Bar</summary>\n\n```\noh no\n```\n\n</details>\n\n"
+	assert.Equal(t, want, buf.String())
+}
+
+func TestDiffWriteMarkdown_htmlInName(t *testing.T) {
+	diff := Diff{
+		NewlyFailing: []TestChange{{
+			Name:       "ListTest<T>.Add & Remove",
+			BaseStatus: response.TestResultStatusSuccess,
+			HeadStatus: response.TestResultStatusFailed,
+			Message:    "oh no",
+		}},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, diff.WriteMarkdown(&buf))
+	assert.Contains(t, buf.String(), "<summary>ListTest&lt;T&gt;.Add &amp; Remove</summary>")
+}
+
+func TestDiffWriteText_empty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, Diff{BaseBuildID: 1, HeadBuildID: 2}.WriteText(&buf))
+	assert.Equal(t, "Test results: build #2 compared to build #1\nNo changes in test results.\n", buf.String())
+}
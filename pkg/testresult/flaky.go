@@ -0,0 +1,126 @@
+package testresult
+
+import (
+	"sort"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+)
+
+// DefaultMinFlips is the default value of FlakyOptions.MinFlips.
+const DefaultMinFlips = 2
+
+// BuildDetails is the test result details of a single build.
+type BuildDetails struct {
+	BuildID uint
+	// GitBranch is the branch the build was run on. Test statuses are only
+	// compared between builds of the same branch.
+	GitBranch string
+	Details   []response.TestResultDetail
+}
+
+// FlakyOptions holds optional settings used when detecting flaky tests.
+type FlakyOptions struct {
+	// MinFlips is the minimum number of times a test must change between
+	// Success and Failed to be considered flaky. A single flip is more likely
+	// a regression or a fix, and is therefore not enough. Defaults to 2.
+	MinFlips int
+}
+
+// FlakyTest is a test that has changed status between Success and Failed
+// multiple times over a series of builds.
+type FlakyTest struct {
+	Name string
+	// GitBranch is the branch of the builds where the test flipped.
+	GitBranch string
+	// Flips is the number of times the test changed between Success and
+	// Failed, ignoring builds where it was skipped or missing.
+	Flips int
+	// Runs is the number of builds where the test passed or failed.
+	Runs int
+	// Failures is the number of builds where the test failed.
+	Failures int
+	// FirstSeenBuildID is the ID of the oldest build containing the test.
+	FirstSeenBuildID uint
+	// LastFailedBuildID is the ID of the newest build where the test failed.
+	LastFailedBuildID uint
+	// LastFailureMessage is the test's message from the newest build where
+	// the test failed.
+	LastFailureMessage string
+}
+
+type flakyKey struct {
+	gitBranch string
+	name      string
+}
+
+type flakyState struct {
+	test       FlakyTest
+	lastStatus response.TestResultStatus
+}
+
+// DetectFlaky walks the builds from oldest to newest, based on their build
+// IDs, and returns the tests that flipped between Success and Failed at least
+// FlakyOptions.MinFlips times. Flips are counted separately per
+// BuildDetails.GitBranch, so a test that only fails on one branch is not
+// considered flaky. The result is sorted with the most flaky tests first.
+func DetectFlaky(builds []BuildDetails, opts FlakyOptions) []FlakyTest {
+	if opts.MinFlips <= 0 {
+		opts.MinFlips = DefaultMinFlips
+	}
+	sorted := make([]BuildDetails, len(builds))
+	copy(sorted, builds)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].BuildID < sorted[j].BuildID
+	})
+
+	states := map[flakyKey]*flakyState{}
+	var keys []flakyKey
+	for _, build := range sorted {
+		tests, buildNames := aggregateByName(build.Details)
+		for _, name := range buildNames {
+			agg := tests[name]
+			key := flakyKey{build.GitBranch, name}
+			state, ok := states[key]
+			if !ok {
+				state = &flakyState{test: FlakyTest{
+					Name:             name,
+					GitBranch:        build.GitBranch,
+					FirstSeenBuildID: build.BuildID,
+				}}
+				states[key] = state
+				keys = append(keys, key)
+			}
+			if agg.status != response.TestResultStatusSuccess &&
+				agg.status != response.TestResultStatusFailed {
+				continue
+			}
+			state.test.Runs++
+			if agg.status == response.TestResultStatusFailed {
+				state.test.Failures++
+				state.test.LastFailedBuildID = build.BuildID
+				state.test.LastFailureMessage = agg.message
+			}
+			if state.lastStatus != "" && state.lastStatus != agg.status {
+				state.test.Flips++
+			}
+			state.lastStatus = agg.status
+		}
+	}
+
+	var flaky []FlakyTest
+	for _, key := range keys {
+		if test := states[key].test; test.Flips >= opts.MinFlips {
+			flaky = append(flaky, test)
+		}
+	}
+	sort.SliceStable(flaky, func(i, j int) bool {
+		if flaky[i].Flips != flaky[j].Flips {
+			return flaky[i].Flips > flaky[j].Flips
+		}
+		if flaky[i].Name != flaky[j].Name {
+			return flaky[i].Name < flaky[j].Name
+		}
+		return flaky[i].GitBranch < flaky[j].GitBranch
+	})
+	return flaky
+}
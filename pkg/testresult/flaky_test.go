@@ -0,0 +1,89 @@
+package testresult
+
+import (
+	"testing"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v4"
+)
+
+func TestDetectFlaky(t *testing.T) {
+	const (
+		success = response.TestResultStatusSuccess
+		failed  = response.TestResultStatusFailed
+		skipped = response.TestResultStatusSkipped
+	)
+	build := func(id uint, statuses map[string]response.TestResultStatus) BuildDetails {
+		b := BuildDetails{BuildID: id}
+		for _, name := range []string{"Flaky", "Stable", "Regressed", "SkippedBetween"} {
+			status, ok := statuses[name]
+			if !ok {
+				continue
+			}
+			d := response.TestResultDetail{Name: name, Status: status}
+			if status == failed {
+				d.Message = null.StringFrom(name + " failed in build")
+			}
+			b.Details = append(b.Details, d)
+		}
+		return b
+	}
+	builds := []BuildDetails{
+		// Given newest first, as returned by wharf-api.
+		build(5, map[string]response.TestResultStatus{"Flaky": success, "Stable": success, "Regressed": failed, "SkippedBetween": failed}),
+		build(4, map[string]response.TestResultStatus{"Flaky": failed, "Stable": success, "Regressed": failed, "SkippedBetween": skipped}),
+		build(3, map[string]response.TestResultStatus{"Flaky": success, "Stable": success, "Regressed": success, "SkippedBetween": success}),
+		build(2, map[string]response.TestResultStatus{"Flaky": failed, "Stable": success, "Regressed": success}),
+		build(1, map[string]response.TestResultStatus{"Stable": success, "Regressed": success}),
+	}
+
+	got := DetectFlaky(builds, FlakyOptions{})
+	require.Len(t, got, 1)
+	assert.Equal(t, FlakyTest{
+		Name:               "Flaky",
+		Flips:              3,
+		Runs:               4,
+		Failures:           2,
+		FirstSeenBuildID:   2,
+		LastFailedBuildID:  4,
+		LastFailureMessage: "Flaky failed in build",
+	}, got[0])
+
+	got = DetectFlaky(builds, FlakyOptions{MinFlips: 1})
+	var names []string
+	for _, test := range got {
+		names = append(names, test.Name)
+	}
+	assert.Equal(t, []string{"Flaky", "Regressed", "SkippedBetween"}, names)
+}
+
+func TestDetectFlaky_perBranch(t *testing.T) {
+	build := func(id uint, branch string, status response.TestResultStatus) BuildDetails {
+		return BuildDetails{
+			BuildID:   id,
+			GitBranch: branch,
+			Details:   []response.TestResultDetail{{Name: "Foo", Status: status}},
+		}
+	}
+	// Fails consistently on the feature branch, and passes consistently on
+	// master, which must not be counted as flips when interleaved.
+	builds := []BuildDetails{
+		build(1, "master", response.TestResultStatusSuccess),
+		build(2, "feature", response.TestResultStatusFailed),
+		build(3, "master", response.TestResultStatusSuccess),
+		build(4, "feature", response.TestResultStatusFailed),
+		build(5, "master", response.TestResultStatusSuccess),
+		build(6, "feature", response.TestResultStatusSuccess),
+		build(7, "feature", response.TestResultStatusFailed),
+	}
+
+	got := DetectFlaky(builds, FlakyOptions{})
+	require.Len(t, got, 1)
+	assert.Equal(t, "Foo", got[0].Name)
+	assert.Equal(t, "feature", got[0].GitBranch)
+	assert.Equal(t, 2, got[0].Flips)
+	assert.Equal(t, 4, got[0].Runs)
+	assert.Equal(t, uint(2), got[0].FirstSeenBuildID)
+}
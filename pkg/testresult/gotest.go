@@ -0,0 +1,194 @@
+package testresult
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"gopkg.in/guregu/null.v4"
+)
+
+// goTestEvent is a single line of output from "go test -json", as documented
+// in "go doc test2json".
+type goTestEvent struct {
+	Time    time.Time `json:"Time"`
+	Action  string    `json:"Action"`
+	Package string    `json:"Package"`
+	Test    string    `json:"Test"`
+	Elapsed float64   `json:"Elapsed"`
+	Output  string    `json:"Output"`
+}
+
+type goTestState struct {
+	detail response.TestResultDetail
+	output strings.Builder
+	done   bool
+	pkg    *goPackageState
+}
+
+type goPackageState struct {
+	output        strings.Builder
+	failed        bool
+	hasFailedTest bool
+	completedOn   time.Time
+}
+
+// ParseGoTestJSON parses the output of "go test -json".
+//
+// Tests are named by their package and test name, such as
+// "example.com/pkg.TestFoo/subtest". Package-level failures that are not
+// attributed to any test, such as compilation errors or panics in TestMain,
+// are reported as a failed test named after the package. Tests that never
+// finished, such as due to a panic or a timeout, are reported as failed, in
+// place of the package-level failure.
+func ParseGoTestJSON(r io.Reader) (Result, error) {
+	var testOrder, pkgOrder []string
+	tests := map[string]*goTestState{}
+	pkgs := map[string]*goPackageState{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var ev goTestEvent
+		if err := json.Unmarshal([]byte(line), &ev); err != nil {
+			return Result{}, fmt.Errorf("decode go test json: line %d: %w", lineNum, err)
+		}
+		pkg, ok := pkgs[ev.Package]
+		if !ok {
+			pkg = &goPackageState{}
+			pkgs[ev.Package] = pkg
+			pkgOrder = append(pkgOrder, ev.Package)
+		}
+		if ev.Test == "" {
+			switch ev.Action {
+			case "output":
+				pkg.output.WriteString(ev.Output)
+			case "fail":
+				pkg.failed = true
+				pkg.completedOn = ev.Time
+			}
+			continue
+		}
+		name := goTestName(ev.Package, ev.Test)
+		test, ok := tests[name]
+		if !ok {
+			test = &goTestState{
+				detail: response.TestResultDetail{Name: name},
+				pkg:    pkg,
+			}
+			tests[name] = test
+			testOrder = append(testOrder, name)
+		}
+		switch ev.Action {
+		case "run":
+			if !ev.Time.IsZero() {
+				test.detail.StartedOn = null.TimeFrom(ev.Time)
+			}
+		case "output":
+			if !isGoTestFramingOutput(ev.Output) {
+				test.output.WriteString(ev.Output)
+			}
+		case "pass", "fail", "skip":
+			test.done = true
+			test.detail.Status = goTestActionToStatus(ev.Action)
+			if ev.Action == "fail" {
+				pkg.hasFailedTest = true
+			}
+			if !ev.Time.IsZero() {
+				test.detail.CompletedOn = null.TimeFrom(ev.Time)
+				if !test.detail.StartedOn.Valid {
+					elapsed := time.Duration(ev.Elapsed * float64(time.Second))
+					test.detail.StartedOn = null.TimeFrom(ev.Time.Add(-elapsed))
+				}
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Result{}, fmt.Errorf("read go test json: %w", err)
+	}
+
+	details := make([]response.TestResultDetail, 0, len(testOrder))
+	for _, name := range testOrder {
+		test := tests[name]
+		if !test.done {
+			test.detail.Status = response.TestResultStatusFailed
+			test.output.WriteString("test did not finish\n")
+			// The package failure is already reported by this test.
+			test.pkg.hasFailedTest = true
+		}
+		if test.detail.Status != response.TestResultStatusSuccess {
+			if msg := strings.TrimSpace(test.output.String()); msg != "" {
+				test.detail.Message = null.StringFrom(msg)
+			}
+		}
+		details = append(details, test.detail)
+	}
+	for _, name := range pkgOrder {
+		pkg := pkgs[name]
+		if !pkg.failed || pkg.hasFailedTest {
+			continue
+		}
+		detail := response.TestResultDetail{
+			Name:   name,
+			Status: response.TestResultStatusFailed,
+		}
+		if !pkg.completedOn.IsZero() {
+			detail.CompletedOn = null.TimeFrom(pkg.completedOn)
+		}
+		if msg := strings.TrimSpace(pkg.output.String()); msg != "" {
+			detail.Message = null.StringFrom(msg)
+		}
+		details = append(details, detail)
+	}
+	return newResult(details), nil
+}
+
+// goTestFramingPrefixes are the prefixes of the lines "go test" prints to mark
+// the start and end of each test, which are not part of the test's own output.
+var goTestFramingPrefixes = []string{
+	"=== RUN ",
+	"=== PAUSE ",
+	"=== CONT ",
+	"=== NAME ",
+	"--- PASS: ",
+	"--- FAIL: ",
+	"--- SKIP: ",
+}
+
+func isGoTestFramingOutput(output string) bool {
+	line := strings.TrimLeft(output, " ")
+	for _, prefix := range goTestFramingPrefixes {
+		if strings.HasPrefix(line, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func goTestName(pkg, test string) string {
+	if pkg == "" {
+		return test
+	}
+	return pkg + "." + test
+}
+
+func goTestActionToStatus(action string) response.TestResultStatus {
+	switch action {
+	case "pass":
+		return response.TestResultStatusSuccess
+	case "skip":
+		return response.TestResultStatusSkipped
+	default:
+		return response.TestResultStatusFailed
+	}
+}
@@ -0,0 +1,87 @@
+package testresult
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testGoTestJSON = `
+{"Time":"2022-05-01T12:00:00Z","Action":"run","Package":"example.com/foo","Test":"TestPasses"}
+{"Time":"2022-05-01T12:00:00.5Z","Action":"pass","Package":"example.com/foo","Test":"TestPasses","Elapsed":0.5}
+{"Time":"2022-05-01T12:00:01Z","Action":"run","Package":"example.com/foo","Test":"TestFails"}
+{"Time":"2022-05-01T12:00:01Z","Action":"output","Package":"example.com/foo","Test":"TestFails","Output":"=== RUN   TestFails\n"}
+{"Time":"2022-05-01T12:00:01Z","Action":"output","Package":"example.com/foo","Test":"TestFails","Output":"=== PAUSE TestFails\n"}
+{"Time":"2022-05-01T12:00:01Z","Action":"output","Package":"example.com/foo","Test":"TestFails","Output":"=== CONT  TestFails\n"}
+{"Time":"2022-05-01T12:00:01Z","Action":"output","Package":"example.com/foo","Test":"TestFails","Output":"    foo_test.go:12: oh no\n"}
+{"Time":"2022-05-01T12:00:01.1Z","Action":"output","Package":"example.com/foo","Test":"TestFails","Output":"--- FAIL: TestFails (0.10s)\n"}
+{"Time":"2022-05-01T12:00:01.1Z","Action":"fail","Package":"example.com/foo","Test":"TestFails","Elapsed":0.1}
+{"Time":"2022-05-01T12:00:02Z","Action":"run","Package":"example.com/foo","Test":"TestSkips"}
+{"Time":"2022-05-01T12:00:02Z","Action":"output","Package":"example.com/foo","Test":"TestSkips","Output":"=== RUN   TestSkips\n"}
+{"Time":"2022-05-01T12:00:02Z","Action":"output","Package":"example.com/foo","Test":"TestSkips","Output":"    foo_test.go:20: later\n"}
+{"Time":"2022-05-01T12:00:02Z","Action":"output","Package":"example.com/foo","Test":"TestSkips","Output":"--- SKIP: TestSkips (0.00s)\n"}
+{"Time":"2022-05-01T12:00:02Z","Action":"skip","Package":"example.com/foo","Test":"TestSkips","Elapsed":0}
+{"Time":"2022-05-01T12:00:03Z","Action":"fail","Package":"example.com/foo","Elapsed":3}
+{"Time":"2022-05-01T12:00:03Z","Action":"output","Package":"example.com/bar","Output":"bar.go:3:1: syntax error\n"}
+{"Time":"2022-05-01T12:00:03Z","Action":"fail","Package":"example.com/bar","Elapsed":0}
+{"Time":"2022-05-01T12:00:03Z","Action":"pass","Package":"example.com/moo","Elapsed":0}
+`
+
+func TestParseGoTestJSON(t *testing.T) {
+	res, err := ParseGoTestJSON(strings.NewReader(testGoTestJSON))
+	require.NoError(t, err)
+	assert.Equal(t, response.TestResultSummary{Total: 4, Passed: 1, Failed: 2, Skipped: 1}, res.Summary)
+	require.Len(t, res.Details, 4)
+
+	passed := res.Details[0]
+	assert.Equal(t, "example.com/foo.TestPasses", passed.Name)
+	assert.Equal(t, 500*time.Millisecond, passed.CompletedOn.Time.Sub(passed.StartedOn.Time))
+
+	failed := res.Details[1]
+	assert.Equal(t, response.TestResultStatusFailed, failed.Status)
+	assert.Equal(t, "foo_test.go:12: oh no", failed.Message.String)
+
+	skipped := res.Details[2]
+	assert.Equal(t, response.TestResultStatusSkipped, skipped.Status)
+	assert.Equal(t, "foo_test.go:20: later", skipped.Message.String)
+
+	buildFailure := res.Details[3]
+	assert.Equal(t, "example.com/bar", buildFailure.Name)
+	assert.Equal(t, response.TestResultStatusFailed, buildFailure.Status)
+	assert.Equal(t, "bar.go:3:1: syntax error", buildFailure.Message.String)
+}
+
+func TestParseGoTestJSON_panic(t *testing.T) {
+	res, err := ParseGoTestJSON(strings.NewReader(`
+{"Action":"run","Package":"p","Test":"TestPanics"}
+{"Action":"output","Package":"p","Test":"TestPanics","Output":"=== RUN   TestPanics\n"}
+{"Action":"output","Package":"p","Test":"TestPanics","Output":"panic: boom\n"}
+{"Action":"output","Package":"p","Test":"TestPanics","Output":"\n"}
+{"Action":"output","Package":"p","Test":"TestPanics","Output":"goroutine 7 [running]:\n"}
+{"Action":"output","Package":"p","Output":"FAIL\tp\t0.01s\n"}
+{"Action":"fail","Package":"p","Elapsed":0.01}
+`))
+	require.NoError(t, err)
+	assert.Equal(t, response.TestResultSummary{Total: 1, Failed: 1}, res.Summary)
+	require.Len(t, res.Details, 1)
+	assert.Equal(t, "p.TestPanics", res.Details[0].Name)
+	assert.Equal(t, response.TestResultStatusFailed, res.Details[0].Status)
+	assert.Contains(t, res.Details[0].Message.String, "panic: boom")
+}
+
+func TestParseGoTestJSON_unfinishedTest(t *testing.T) {
+	res, err := ParseGoTestJSON(strings.NewReader(
+		`{"Action":"run","Package":"p","Test":"TestHangs"}`))
+	require.NoError(t, err)
+	require.Len(t, res.Details, 1)
+	assert.Equal(t, response.TestResultStatusFailed, res.Details[0].Status)
+}
+
+func TestParseGoTestJSON_invalid(t *testing.T) {
+	_, err := ParseGoTestJSON(strings.NewReader("not json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,180 @@
+package testresult
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"gopkg.in/guregu/null.v4"
+)
+
+type junitTestSuite struct {
+	Name      string           `xml:"name,attr"`
+	Timestamp string           `xml:"timestamp,attr"`
+	Suites    []junitTestSuite `xml:"testsuite"`
+	Cases     []junitTestCase  `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure"`
+	Error     *junitMessage `xml:"error"`
+	Skipped   *junitMessage `xml:"skipped"`
+	SystemOut string        `xml:"system-out"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// junitTimestampLayouts are the layouts tried when parsing the timestamp
+// attribute, where timestamps without a time zone are assumed to be in UTC.
+var junitTimestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+}
+
+// junitFallbackStart is used as start time of test suites without a
+// timestamp, so that the test durations are still kept.
+var junitFallbackStart = time.Unix(0, 0).UTC()
+
+// ParseJUnit parses a JUnit XML file, where the root element is either a
+// <testsuites> or a single <testsuite> element.
+//
+// JUnit only stores the start time of each test suite, so tests are assumed to
+// run sequentially in the order they are listed. Test suites without a
+// timestamp start where their parent suite left off, or at the Unix epoch.
+func ParseJUnit(r io.Reader) (Result, error) {
+	dec := xml.NewDecoder(r)
+	root, err := findRootElement(dec)
+	if err != nil {
+		return Result{}, fmt.Errorf("decode junit: %w", err)
+	}
+	var suite junitTestSuite
+	switch root.Name.Local {
+	case "testsuites", "testsuite":
+		if err := dec.DecodeElement(&suite, &root); err != nil {
+			return Result{}, fmt.Errorf("decode junit: %w", err)
+		}
+	default:
+		return Result{}, fmt.Errorf("decode junit: unexpected root element <%s>, expected <testsuites> or <testsuite>", root.Name.Local)
+	}
+	var details []response.TestResultDetail
+	if _, err := appendJUnitDetails(&details, suite, junitFallbackStart); err != nil {
+		return Result{}, err
+	}
+	return newResult(details), nil
+}
+
+func findRootElement(dec *xml.Decoder) (xml.StartElement, error) {
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			if err == io.EOF {
+				return xml.StartElement{}, io.ErrUnexpectedEOF
+			}
+			return xml.StartElement{}, err
+		}
+		if start, ok := tok.(xml.StartElement); ok {
+			return start, nil
+		}
+	}
+}
+
+// appendJUnitDetails appends the details of the suite and its child suites,
+// and returns the time the last test completed. The start time is used if the
+// suite has no timestamp.
+func appendJUnitDetails(details *[]response.TestResultDetail, suite junitTestSuite, startedOn time.Time) (time.Time, error) {
+	if suite.Timestamp != "" {
+		t, err := parseJUnitTimestamp(suite.Timestamp)
+		if err != nil {
+			return startedOn, fmt.Errorf("test suite %q: %w", suite.Name, err)
+		}
+		startedOn = t
+	}
+	for _, tc := range suite.Cases {
+		detail, duration, err := tc.toDetail()
+		if err != nil {
+			return startedOn, fmt.Errorf("test case %q: %w", tc.Name, err)
+		}
+		detail.StartedOn = null.TimeFrom(startedOn)
+		startedOn = startedOn.Add(duration)
+		detail.CompletedOn = null.TimeFrom(startedOn)
+		*details = append(*details, detail)
+	}
+	for _, child := range suite.Suites {
+		var err error
+		startedOn, err = appendJUnitDetails(details, child, startedOn)
+		if err != nil {
+			return startedOn, err
+		}
+	}
+	return startedOn, nil
+}
+
+func (tc junitTestCase) toDetail() (response.TestResultDetail, time.Duration, error) {
+	name := tc.Name
+	if tc.ClassName != "" {
+		name = tc.ClassName + "." + tc.Name
+	}
+	detail := response.TestResultDetail{
+		Name:   name,
+		Status: response.TestResultStatusSuccess,
+	}
+	switch {
+	case tc.Failure != nil:
+		detail.Status = response.TestResultStatusFailed
+		detail.Message = tc.Failure.toNullString()
+	case tc.Error != nil:
+		detail.Status = response.TestResultStatusFailed
+		detail.Message = tc.Error.toNullString()
+	case tc.Skipped != nil:
+		detail.Status = response.TestResultStatusSkipped
+		detail.Message = tc.Skipped.toNullString()
+	}
+	var duration time.Duration
+	if tc.Time != "" {
+		secs, err := strconv.ParseFloat(strings.ReplaceAll(tc.Time, ",", ""), 64)
+		if err != nil {
+			return response.TestResultDetail{}, 0, fmt.Errorf("parse time: %w", err)
+		}
+		duration = time.Duration(secs * float64(time.Second))
+	}
+	return detail, duration, nil
+}
+
+func (m *junitMessage) toNullString() null.String {
+	msg := strings.TrimSpace(m.Message)
+	if text := strings.TrimSpace(m.Text); text != "" && text != msg {
+		if msg != "" {
+			msg += "\n"
+		}
+		msg += text
+	}
+	if msg == "" {
+		return null.String{}
+	}
+	return null.StringFrom(msg)
+}
+
+func parseJUnitTimestamp(s string) (time.Time, error) {
+	var firstErr error
+	for _, layout := range junitTimestampLayouts {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return t, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return time.Time{}, fmt.Errorf("parse timestamp: %w", firstErr)
+}
@@ -0,0 +1,73 @@
+package testresult
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testJUnit = `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites>
+  <testsuite name="com.example.FooTest" timestamp="2022-05-01T12:00:00" tests="4">
+    <testcase classname="com.example.FooTest" name="passes" time="1.5"/>
+    <testcase classname="com.example.FooTest" name="fails" time="0.25">
+      <failure message="expected 1 but was 2" type="AssertionError">stack trace here</failure>
+    </testcase>
+    <testcase classname="com.example.FooTest" name="errors">
+      <error message="boom"/>
+    </testcase>
+    <testcase classname="com.example.FooTest" name="skipped">
+      <skipped message="not ready"/>
+    </testcase>
+  </testsuite>
+</testsuites>`
+
+func TestParseJUnit(t *testing.T) {
+	res, err := ParseJUnit(strings.NewReader(testJUnit))
+	require.NoError(t, err)
+	assert.Equal(t, response.TestResultSummary{Total: 4, Passed: 1, Failed: 2, Skipped: 1}, res.Summary)
+	require.Len(t, res.Details, 4)
+
+	passed := res.Details[0]
+	assert.Equal(t, "com.example.FooTest.passes", passed.Name)
+	assert.Equal(t, time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC), passed.StartedOn.Time)
+	assert.Equal(t, 1500*time.Millisecond, passed.CompletedOn.Time.Sub(passed.StartedOn.Time))
+
+	failed := res.Details[1]
+	assert.Equal(t, response.TestResultStatusFailed, failed.Status)
+	assert.Equal(t, "expected 1 but was 2\nstack trace here", failed.Message.String)
+	assert.Equal(t, passed.CompletedOn.Time, failed.StartedOn.Time)
+
+	assert.Equal(t, "boom", res.Details[2].Message.String)
+	assert.Equal(t, response.TestResultStatusSkipped, res.Details[3].Status)
+	assert.Equal(t, "not ready", res.Details[3].Message.String)
+}
+
+func TestParseJUnit_singleSuiteRoot(t *testing.T) {
+	res, err := ParseJUnit(strings.NewReader(`<testsuite name="x"><testcase name="a"/></testsuite>`))
+	require.NoError(t, err)
+	require.Len(t, res.Details, 1)
+	assert.Equal(t, "a", res.Details[0].Name)
+}
+
+func TestParseJUnit_noTimestamp(t *testing.T) {
+	res, err := ParseJUnit(strings.NewReader(`<testsuite name="x">
+  <testcase name="a" time="1.5"/>
+  <testcase name="b" time="0.25"/>
+</testsuite>`))
+	require.NoError(t, err)
+	require.Len(t, res.Details, 2)
+	a, b := res.Details[0], res.Details[1]
+	assert.Equal(t, 1500*time.Millisecond, a.CompletedOn.Time.Sub(a.StartedOn.Time))
+	assert.Equal(t, 250*time.Millisecond, b.CompletedOn.Time.Sub(b.StartedOn.Time))
+	assert.Equal(t, a.CompletedOn.Time, b.StartedOn.Time)
+}
+
+func TestParseJUnit_invalidRoot(t *testing.T) {
+	_, err := ParseJUnit(strings.NewReader(`<TestRun></TestRun>`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,132 @@
+package testresult
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+)
+
+type junitWriteTestSuites struct {
+	XMLName  xml.Name              `xml:"testsuites"`
+	Name     string                `xml:"name,attr,omitempty"`
+	Tests    uint                  `xml:"tests,attr"`
+	Failures uint                  `xml:"failures,attr"`
+	Skipped  uint                  `xml:"skipped,attr"`
+	Time     string                `xml:"time,attr"`
+	Suites   []junitWriteTestSuite `xml:"testsuite"`
+}
+
+type junitWriteTestSuite struct {
+	Name      string               `xml:"name,attr"`
+	Tests     uint                 `xml:"tests,attr"`
+	Failures  uint                 `xml:"failures,attr"`
+	Errors    uint                 `xml:"errors,attr"`
+	Skipped   uint                 `xml:"skipped,attr"`
+	Time      string               `xml:"time,attr"`
+	Timestamp string               `xml:"timestamp,attr,omitempty"`
+	Cases     []junitWriteTestCase `xml:"testcase"`
+}
+
+type junitWriteTestCase struct {
+	Name      string             `xml:"name,attr"`
+	ClassName string             `xml:"classname,attr,omitempty"`
+	Time      string             `xml:"time,attr"`
+	Failure   *junitWriteMessage `xml:"failure,omitempty"`
+	Skipped   *junitWriteMessage `xml:"skipped,omitempty"`
+	SystemOut string             `xml:"system-out,omitempty"`
+}
+
+type junitWriteMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the report as a JUnit XML file, with one <testsuite>
+// element per test result file. Test names are split on their last dot into
+// the test case's class name and name, which is the inverse of how ParseJUnit
+// names the tests.
+func (r Report) WriteJUnit(w io.Writer) error {
+	root := junitWriteTestSuites{
+		Name:     "build #" + strconv.FormatUint(uint64(r.BuildID), 10),
+		Tests:    r.ListSummary.Total,
+		Failures: r.ListSummary.Failed,
+		Skipped:  r.ListSummary.Skipped,
+	}
+	var total time.Duration
+	for _, f := range r.Files() {
+		suite, duration := newJUnitWriteTestSuite(f)
+		total += duration
+		root.Suites = append(root.Suites, suite)
+	}
+	root.Time = formatJUnitSeconds(total)
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(root); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func newJUnitWriteTestSuite(f ReportFile) (junitWriteTestSuite, time.Duration) {
+	suite := junitWriteTestSuite{
+		Name:     f.Summary.FileName,
+		Tests:    f.Summary.Total,
+		Failures: f.Summary.Failed,
+		Skipped:  f.Summary.Skipped,
+	}
+	var start time.Time
+	var total time.Duration
+	for _, d := range f.Details {
+		if d.StartedOn.Valid && (start.IsZero() || d.StartedOn.Time.Before(start)) {
+			start = d.StartedOn.Time
+		}
+		duration := DetailDuration(d)
+		total += duration
+		tc := junitWriteTestCase{Time: formatJUnitSeconds(duration)}
+		tc.ClassName, tc.Name = splitTestName(d.Name)
+		var msg string
+		if d.Message.Valid {
+			msg = d.Message.String
+		}
+		switch d.Status {
+		case response.TestResultStatusFailed:
+			message, text := splitFirstLine(msg)
+			tc.Failure = &junitWriteMessage{Message: message, Text: text}
+		case response.TestResultStatusSkipped:
+			message, text := splitFirstLine(msg)
+			tc.Skipped = &junitWriteMessage{Message: message, Text: text}
+		default:
+			tc.SystemOut = msg
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+	if !start.IsZero() {
+		suite.Timestamp = start.UTC().Format("2006-01-02T15:04:05")
+	}
+	suite.Time = formatJUnitSeconds(total)
+	return suite, total
+}
+
+func formatJUnitSeconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
+
+// splitFirstLine splits a test message into its first line, used in the
+// message attribute, and the remaining lines, used as the element's text. This
+// is the inverse of how ParseJUnit merges the two.
+func splitFirstLine(s string) (first, rest string) {
+	idx := strings.IndexByte(s, '\n')
+	if idx == -1 {
+		return s, ""
+	}
+	return s[:idx], s[idx+1:]
+}
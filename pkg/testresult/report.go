@@ -0,0 +1,198 @@
+package testresult
+
+import (
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+)
+
+// Report is all test results of a build, as returned by wharf-api, that can
+// be rendered as Markdown, HTML, or JUnit XML.
+type Report struct {
+	BuildID     uint
+	ListSummary response.TestResultListSummary
+	Summaries   []response.TestResultSummary
+	Details     []response.TestResultDetail
+}
+
+// NewReport creates a report from locally parsed test results, such as from
+// ParseFile. The summaries are recalculated from the test details, where only
+// the file names are kept from the results' summaries.
+func NewReport(buildID uint, results ...Result) Report {
+	report := Report{
+		BuildID:     buildID,
+		ListSummary: response.TestResultListSummary{BuildID: buildID},
+	}
+	for i, res := range results {
+		summary := Summarize(res.Details)
+		summary.FileName = res.Summary.FileName
+		summary.BuildID = buildID
+		// Locally parsed results have no artifact IDs, so use the index to
+		// be able to group the details by file.
+		summary.ArtifactID = uint(i + 1)
+		report.Summaries = append(report.Summaries, summary)
+		report.ListSummary.Total += summary.Total
+		report.ListSummary.Passed += summary.Passed
+		report.ListSummary.Failed += summary.Failed
+		report.ListSummary.Skipped += summary.Skipped
+		for _, d := range res.Details {
+			d.BuildID = buildID
+			d.ArtifactID = summary.ArtifactID
+			report.Details = append(report.Details, d)
+		}
+	}
+	return report
+}
+
+// ReportFile is the test results of a single test result file in a report.
+type ReportFile struct {
+	Summary response.TestResultSummary
+	Details []response.TestResultDetail
+}
+
+// Files groups the report's test details by their test result file, in the
+// same order as the report's summaries. Details that do not belong to any of
+// the summaries are grouped into a trailing file named "other".
+func (r Report) Files() []ReportFile {
+	files := make([]ReportFile, len(r.Summaries))
+	indexByArtifactID := make(map[uint]int, len(r.Summaries))
+	for i, s := range r.Summaries {
+		files[i].Summary = s
+		indexByArtifactID[s.ArtifactID] = i
+	}
+	var other []response.TestResultDetail
+	for _, d := range r.Details {
+		i, ok := indexByArtifactID[d.ArtifactID]
+		if !ok {
+			other = append(other, d)
+			continue
+		}
+		files[i].Details = append(files[i].Details, d)
+	}
+	if len(other) > 0 {
+		summary := Summarize(other)
+		summary.BuildID = r.BuildID
+		summary.FileName = "other"
+		files = append(files, ReportFile{Summary: summary, Details: other})
+	}
+	return files
+}
+
+// FailedDetails returns the failed tests of the report, sorted by name.
+func (r Report) FailedDetails() []response.TestResultDetail {
+	var failed []response.TestResultDetail
+	for _, d := range r.Details {
+		if d.Status == response.TestResultStatusFailed {
+			failed = append(failed, d)
+		}
+	}
+	sort.SliceStable(failed, func(i, j int) bool {
+		return failed[i].Name < failed[j].Name
+	})
+	return failed
+}
+
+// WriteMarkdown writes a summary of the report as Markdown, listing the
+// totals, each test result file, and the failed tests with their messages.
+// Passed and skipped tests are not listed, to keep the output short enough
+// for chat notifications.
+func (r Report) WriteMarkdown(w io.Writer) error {
+	ew := &errWriter{w: w}
+	s := r.ListSummary
+	ew.printf("## Test results: build #%d\n\n", r.BuildID)
+	ew.printf("%s **Total:** %d, **Passed:** %d, **Failed:** %d, **Skipped:** %d\n\n",
+		reportOutcomeEmoji(s), s.Total, s.Passed, s.Failed, s.Skipped)
+	if files := r.Files(); len(files) > 0 {
+		ew.printf("| File | Total | Passed | Failed | Skipped |\n")
+		ew.printf("| --- | ---: | ---: | ---: | ---: |\n")
+		for _, f := range files {
+			ew.printf("| %s | %d | %d | %d | %d |\n", escapeMarkdownTable(f.Summary.FileName),
+				f.Summary.Total, f.Summary.Passed, f.Summary.Failed, f.Summary.Skipped)
+		}
+		ew.printf("\n")
+	}
+	failed := r.FailedDetails()
+	if len(failed) == 0 {
+		return ew.err
+	}
+	ew.printf("### Failed tests (%d)\n\n", len(failed))
+	for _, d := range failed {
+		if !d.Message.Valid || d.Message.String == "" {
+			ew.printf("- %s\n", d.Name)
+			continue
+		}
+		ew.printf("<details><summary>%s</summary>\n\n```\n%s\n```\n\n</details>\n\n",
+			template.HTMLEscapeString(d.Name), strings.ReplaceAll(d.Message.String, "```", "` ` `"))
+	}
+	return ew.err
+}
+
+func reportOutcomeEmoji(s response.TestResultListSummary) string {
+	switch {
+	case s.Failed > 0:
+		return ":x:"
+	case s.Passed > 0:
+		return ":white_check_mark:"
+	default:
+		return ":grey_question:"
+	}
+}
+
+var reportHTMLTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"statusClass": func(status response.TestResultStatus) string {
+		return strings.ToLower(string(status))
+	},
+	"duration": func(d response.TestResultDetail) string {
+		if !d.StartedOn.Valid || !d.CompletedOn.Valid {
+			return ""
+		}
+		return DetailDuration(d).Round(time.Millisecond).String()
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Test results: build #{{.BuildID}}</title>
+<style>
+body { font-family: -apple-system, "Segoe UI", Helvetica, Arial, sans-serif; margin: 2em; color: #24292f; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #d0d7de; padding: 4px 10px; text-align: left; vertical-align: top; }
+td.num { text-align: right; }
+pre { margin: 0; white-space: pre-wrap; }
+.success { color: #1a7f37; }
+.failed { color: #cf222e; font-weight: bold; }
+.skipped { color: #6e7781; }
+</style>
+</head>
+<body>
+<h1>Test results: build #{{.BuildID}}</h1>
+<p>Total: {{.ListSummary.Total}},
+<span class="success">Passed: {{.ListSummary.Passed}}</span>,
+<span class="failed">Failed: {{.ListSummary.Failed}}</span>,
+<span class="skipped">Skipped: {{.ListSummary.Skipped}}</span></p>
+{{- range .Files}}
+<h2>{{.Summary.FileName}}</h2>
+<p>Total: {{.Summary.Total}}, Passed: {{.Summary.Passed}}, Failed: {{.Summary.Failed}}, Skipped: {{.Summary.Skipped}}</p>
+{{- if .Details}}
+<table>
+<tr><th>Test</th><th>Status</th><th>Duration</th><th>Message</th></tr>
+{{- range .Details}}
+<tr><td>{{.Name}}</td><td class="{{statusClass .Status}}">{{.Status}}</td><td class="num">{{duration .}}</td><td>{{if .Message.Valid}}<pre>{{.Message.String}}</pre>{{end}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+{{- end}}
+</body>
+</html>
+`))
+
+// WriteHTML writes the report as a self-contained HTML page, without any
+// external stylesheets or scripts, listing all tests grouped by their test
+// result file.
+func (r Report) WriteHTML(w io.Writer) error {
+	return reportHTMLTemplate.Execute(w, r)
+}
@@ -0,0 +1,90 @@
+package testresult
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v4"
+)
+
+func newTestReport() Report {
+	failing := newTestDetail("Pkg.Class.TestFails", response.TestResultStatusFailed, 2*time.Second)
+	failing.Message = null.StringFrom("expected <1>\ngot 2")
+	return NewReport(42,
+		Result{
+			Summary: response.TestResultSummary{FileName: "unit.trx"},
+			Details: []response.TestResultDetail{
+				newTestDetail("Pkg.Class.TestPasses", response.TestResultStatusSuccess, time.Second),
+				failing,
+			},
+		},
+		Result{
+			Summary: response.TestResultSummary{FileName: "integration.xml"},
+			Details: []response.TestResultDetail{
+				newTestDetail("Pkg.TestSkipped", response.TestResultStatusSkipped, 0),
+			},
+		},
+	)
+}
+
+func TestReportFiles(t *testing.T) {
+	report := newTestReport()
+	report.Details = append(report.Details, response.TestResultDetail{
+		Name:       "Orphan",
+		ArtifactID: 99,
+		Status:     response.TestResultStatusSuccess,
+	})
+	files := report.Files()
+	require.Len(t, files, 3)
+	assert.Len(t, files[0].Details, 2)
+	assert.Len(t, files[1].Details, 1)
+	assert.Equal(t, "other", files[2].Summary.FileName)
+	assert.Equal(t, uint(1), files[2].Summary.Passed)
+}
+
+func TestReportWriteMarkdown(t *testing.T) {
+	report := newTestReport()
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteMarkdown(&buf))
+	md := buf.String()
+	assert.Contains(t, md, "## Test results: build #42")
+	assert.Contains(t, md, ":x: **Total:** 3, **Passed:** 1, **Failed:** 1, **Skipped:** 1")
+	assert.Contains(t, md, "| unit.trx | 2 | 1 | 1 | 0 |")
+	assert.Contains(t, md, "### Failed tests (1)")
+	assert.Contains(t, md, "<summary>Pkg.Class.TestFails</summary>")
+	assert.NotContains(t, md, "TestPasses")
+}
+
+func TestReportWriteHTML(t *testing.T) {
+	report := newTestReport()
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteHTML(&buf))
+	html := buf.String()
+	assert.Contains(t, html, "<h2>unit.trx</h2>")
+	assert.Contains(t, html, `<td class="failed">Failed</td>`)
+	assert.Contains(t, html, "<pre>expected &lt;1&gt;\ngot 2</pre>")
+	assert.NotContains(t, html, "<script")
+	assert.NotContains(t, html, "<link")
+}
+
+func TestReportWriteJUnit_roundTrip(t *testing.T) {
+	report := newTestReport()
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteJUnit(&buf))
+	assert.Contains(t, buf.String(), `<testsuite name="unit.trx" tests="2" failures="1" errors="0" skipped="0" time="3.000" timestamp="2022-05-01T12:00:00">`)
+
+	res, err := ParseJUnit(&buf)
+	require.NoError(t, err)
+	require.Len(t, res.Details, 3)
+	assert.Equal(t, "Pkg.Class.TestPasses", res.Details[0].Name)
+	assert.Equal(t, response.TestResultStatusSuccess, res.Details[0].Status)
+	assert.Equal(t, "Pkg.Class.TestFails", res.Details[1].Name)
+	assert.Equal(t, response.TestResultStatusFailed, res.Details[1].Status)
+	assert.Equal(t, null.StringFrom("expected <1>\ngot 2"), res.Details[1].Message)
+	assert.Equal(t, 2*time.Second, DetailDuration(res.Details[1]))
+	assert.Equal(t, response.TestResultStatusSkipped, res.Details[2].Status)
+}
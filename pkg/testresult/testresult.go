@@ -0,0 +1,140 @@
+// Package testresult contains parsers for common test result file formats,
+// producing the same models as wharf-api does when test results are uploaded,
+// so test results can be validated, summarized, and printed locally before
+// uploading them.
+package testresult
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+)
+
+// Format is an enum of supported test result file formats.
+type Format string
+
+const (
+	// FormatTRX is the Visual Studio test results format, as produced by
+	// "dotnet test --logger trx".
+	FormatTRX Format = "trx"
+	// FormatJUnit is the JUnit XML format, as produced by Maven Surefire,
+	// Gradle, and many other test runners.
+	FormatJUnit Format = "junit"
+	// FormatGoTestJSON is the output of "go test -json".
+	FormatGoTestJSON Format = "go-test-json"
+)
+
+// ErrUnknownFormat is returned when a test result format is not supported.
+var ErrUnknownFormat = errors.New("unknown test result format")
+
+// Result is a parsed test result file.
+type Result struct {
+	Summary response.TestResultSummary
+	Details []response.TestResultDetail
+}
+
+// DetectFormat guesses the test result format based on a file name's
+// extension.
+func DetectFormat(fileName string) (Format, bool) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".trx":
+		return FormatTRX, true
+	case ".xml":
+		return FormatJUnit, true
+	case ".json", ".jsonl":
+		return FormatGoTestJSON, true
+	default:
+		return "", false
+	}
+}
+
+// Parse parses test results of the given format.
+func Parse(format Format, r io.Reader) (Result, error) {
+	switch format {
+	case FormatTRX:
+		return ParseTRX(r)
+	case FormatJUnit:
+		return ParseJUnit(r)
+	case FormatGoTestJSON:
+		return ParseGoTestJSON(r)
+	default:
+		return Result{}, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+}
+
+// ParseFile parses a test result file, where the format is detected from the
+// file name's extension. The Result.Summary.FileName field is set to the file's
+// base name.
+func ParseFile(path string) (Result, error) {
+	format, ok := DetectFormat(path)
+	if !ok {
+		return Result{}, fmt.Errorf("%w: %q", ErrUnknownFormat, filepath.Ext(path))
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return Result{}, err
+	}
+	defer f.Close()
+	res, err := Parse(format, f)
+	if err != nil {
+		return Result{}, fmt.Errorf("parse %s: %w", path, err)
+	}
+	res.Summary.FileName = filepath.Base(path)
+	return res, nil
+}
+
+// Summarize counts the number of passed, failed, and skipped tests.
+func Summarize(details []response.TestResultDetail) response.TestResultSummary {
+	var summary response.TestResultSummary
+	for _, d := range details {
+		summary.Total++
+		switch d.Status {
+		case response.TestResultStatusSuccess:
+			summary.Passed++
+		case response.TestResultStatusFailed:
+			summary.Failed++
+		case response.TestResultStatusSkipped:
+			summary.Skipped++
+		}
+	}
+	return summary
+}
+
+func newResult(details []response.TestResultDetail) Result {
+	return Result{
+		Summary: Summarize(details),
+		Details: details,
+	}
+}
+
+// Fprint writes a human-readable summary of the test results, listing any
+// failed tests together with their messages.
+func Fprint(w io.Writer, res Result) error {
+	s := res.Summary
+	if _, err := fmt.Fprintf(w, "Total: %d, Passed: %d, Failed: %d, Skipped: %d\n",
+		s.Total, s.Passed, s.Failed, s.Skipped); err != nil {
+		return err
+	}
+	for _, d := range res.Details {
+		if d.Status != response.TestResultStatusFailed {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "--- FAIL: %s\n", d.Name); err != nil {
+			return err
+		}
+		if !d.Message.Valid || d.Message.String == "" {
+			continue
+		}
+		for _, line := range strings.Split(strings.TrimRight(d.Message.String, "\n"), "\n") {
+			if _, err := fmt.Fprintf(w, "    %s\n", line); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
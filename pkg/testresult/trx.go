@@ -0,0 +1,130 @@
+package testresult
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"gopkg.in/guregu/null.v4"
+)
+
+type trxTestRun struct {
+	XMLName xml.Name            `xml:"TestRun"`
+	Results []trxUnitTestResult `xml:"Results>UnitTestResult"`
+}
+
+type trxUnitTestResult struct {
+	TestName  string `xml:"testName,attr"`
+	Outcome   string `xml:"outcome,attr"`
+	StartTime string `xml:"startTime,attr"`
+	EndTime   string `xml:"endTime,attr"`
+	Duration  string `xml:"duration,attr"`
+	Output    struct {
+		StdOut    string `xml:"StdOut"`
+		ErrorInfo struct {
+			Message    string `xml:"Message"`
+			StackTrace string `xml:"StackTrace"`
+		} `xml:"ErrorInfo"`
+	} `xml:"Output"`
+}
+
+// ParseTRX parses a Visual Studio test results (TRX) file.
+func ParseTRX(r io.Reader) (Result, error) {
+	var run trxTestRun
+	if err := xml.NewDecoder(r).Decode(&run); err != nil {
+		return Result{}, fmt.Errorf("decode trx: %w", err)
+	}
+	details := make([]response.TestResultDetail, 0, len(run.Results))
+	for _, res := range run.Results {
+		detail, err := res.toDetail()
+		if err != nil {
+			return Result{}, fmt.Errorf("test %q: %w", res.TestName, err)
+		}
+		details = append(details, detail)
+	}
+	return newResult(details), nil
+}
+
+func (res trxUnitTestResult) toDetail() (response.TestResultDetail, error) {
+	if res.TestName == "" {
+		return response.TestResultDetail{}, errors.New("missing testName attribute")
+	}
+	detail := response.TestResultDetail{
+		Name:   res.TestName,
+		Status: trxOutcomeToStatus(res.Outcome),
+	}
+	if res.StartTime != "" {
+		t, err := time.Parse(time.RFC3339Nano, res.StartTime)
+		if err != nil {
+			return response.TestResultDetail{}, fmt.Errorf("parse startTime: %w", err)
+		}
+		detail.StartedOn = null.TimeFrom(t)
+	}
+	switch {
+	case res.EndTime != "":
+		t, err := time.Parse(time.RFC3339Nano, res.EndTime)
+		if err != nil {
+			return response.TestResultDetail{}, fmt.Errorf("parse endTime: %w", err)
+		}
+		detail.CompletedOn = null.TimeFrom(t)
+	case res.Duration != "" && detail.StartedOn.Valid:
+		d, err := parseTRXDuration(res.Duration)
+		if err != nil {
+			return response.TestResultDetail{}, fmt.Errorf("parse duration: %w", err)
+		}
+		detail.CompletedOn = null.TimeFrom(detail.StartedOn.Time.Add(d))
+	}
+	msg := strings.TrimSpace(res.Output.ErrorInfo.Message)
+	if stack := strings.TrimSpace(res.Output.ErrorInfo.StackTrace); stack != "" {
+		if msg != "" {
+			msg += "\n"
+		}
+		msg += stack
+	}
+	if msg == "" && detail.Status == response.TestResultStatusSkipped {
+		msg = strings.TrimSpace(res.Output.StdOut)
+	}
+	if msg != "" {
+		detail.Message = null.StringFrom(msg)
+	}
+	return detail, nil
+}
+
+func trxOutcomeToStatus(outcome string) response.TestResultStatus {
+	switch outcome {
+	case "Passed", "PassedButRunAborted", "Warning":
+		return response.TestResultStatusSuccess
+	case "NotExecuted", "Inconclusive", "NotRunnable", "Disconnected", "Pending":
+		return response.TestResultStatusSkipped
+	default:
+		return response.TestResultStatusFailed
+	}
+}
+
+// parseTRXDuration parses durations on the format "hh:mm:ss.fffffff".
+func parseTRXDuration(s string) (time.Duration, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid duration: %q", s)
+	}
+	hours, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration: %q", s)
+	}
+	minutes, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration: %q", s)
+	}
+	seconds, err := strconv.ParseFloat(parts[2], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration: %q", s)
+	}
+	return time.Duration(hours)*time.Hour +
+		time.Duration(minutes)*time.Minute +
+		time.Duration(seconds*float64(time.Second)), nil
+}
@@ -0,0 +1,70 @@
+package testresult
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTRX = `<?xml version="1.0" encoding="utf-8"?>
+<TestRun id="1" name="run" xmlns="http://microsoft.com/schemas/VisualStudio/TeamTest/2010">
+  <Results>
+    <UnitTestResult testId="a" testName="Foo.Passes" outcome="Passed"
+      startTime="2022-05-01T12:00:00.0000000+00:00" endTime="2022-05-01T12:00:01.5000000+00:00" duration="00:00:01.5000000" />
+    <UnitTestResult testId="b" testName="Foo.Fails" outcome="Failed"
+      startTime="2022-05-01T12:00:00.0000000+00:00" duration="00:00:00.2500000">
+      <Output>
+        <ErrorInfo>
+          <Message>Assert.Equal() Failure</Message>
+          <StackTrace>at Foo.Fails() in Foo.cs:line 12</StackTrace>
+        </ErrorInfo>
+      </Output>
+    </UnitTestResult>
+    <UnitTestResult testId="c" testName="Foo.Skipped" outcome="NotExecuted">
+      <Output><StdOut>Not ready yet</StdOut></Output>
+    </UnitTestResult>
+  </Results>
+</TestRun>`
+
+func TestParseTRX(t *testing.T) {
+	res, err := ParseTRX(strings.NewReader(testTRX))
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), res.Summary.Total)
+	assert.Equal(t, uint(1), res.Summary.Passed)
+	assert.Equal(t, uint(1), res.Summary.Failed)
+	assert.Equal(t, uint(1), res.Summary.Skipped)
+	require.Len(t, res.Details, 3)
+
+	passed := res.Details[0]
+	assert.Equal(t, "Foo.Passes", passed.Name)
+	assert.Equal(t, response.TestResultStatusSuccess, passed.Status)
+	assert.Equal(t, 1500*time.Millisecond, passed.CompletedOn.Time.Sub(passed.StartedOn.Time))
+	assert.False(t, passed.Message.Valid, "passed test has message")
+
+	failed := res.Details[1]
+	assert.Equal(t, response.TestResultStatusFailed, failed.Status)
+	assert.Equal(t, "Assert.Equal() Failure\nat Foo.Fails() in Foo.cs:line 12", failed.Message.String)
+	assert.Equal(t, 250*time.Millisecond, failed.CompletedOn.Time.Sub(failed.StartedOn.Time))
+
+	skipped := res.Details[2]
+	assert.Equal(t, response.TestResultStatusSkipped, skipped.Status)
+	assert.Equal(t, "Not ready yet", skipped.Message.String)
+}
+
+func TestParseTRX_invalid(t *testing.T) {
+	_, err := ParseTRX(strings.NewReader(`<testsuites></testsuites>`))
+	assert.Error(t, err)
+}
+
+func TestParseTRXDuration(t *testing.T) {
+	got, err := parseTRXDuration("01:02:03.5000000")
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour+2*time.Minute+3500*time.Millisecond, got)
+
+	_, err = parseTRXDuration("1.5")
+	assert.Error(t, err)
+}
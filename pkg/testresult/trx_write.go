@@ -0,0 +1,244 @@
+package testresult
+
+import (
+	"crypto/sha1"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+)
+
+const (
+	// trxUnitTestType is the well-known test type ID of unit tests.
+	trxUnitTestType = "13cdc9d9-ddb5-4fa4-a97d-d965ccfc6d4b"
+	// trxResultsNotInAListID is the well-known ID of the default test list.
+	trxResultsNotInAListID = "8c84fa94-04c1-424b-9868-57a2d4851a5d"
+	// trxAllLoadedResultsID is the well-known ID of the "all results" list.
+	trxAllLoadedResultsID = "19431567-8539-422a-85d7-44ee4e166bda"
+	trxTimeLayout         = "2006-01-02T15:04:05.0000000Z07:00"
+)
+
+type trxWriteTestRun struct {
+	XMLName         xml.Name                 `xml:"http://microsoft.com/schemas/VisualStudio/TeamTest/2010 TestRun"`
+	ID              string                   `xml:"id,attr"`
+	Name            string                   `xml:"name,attr"`
+	Times           *trxWriteTimes           `xml:"Times,omitempty"`
+	Results         []trxWriteUnitTestResult `xml:"Results>UnitTestResult"`
+	TestDefinitions []trxWriteUnitTest       `xml:"TestDefinitions>UnitTest"`
+	TestEntries     []trxWriteTestEntry      `xml:"TestEntries>TestEntry"`
+	TestLists       []trxWriteTestList       `xml:"TestLists>TestList"`
+	ResultSummary   trxWriteResultSummary    `xml:"ResultSummary"`
+}
+
+type trxWriteTimes struct {
+	Creation string `xml:"creation,attr"`
+	Start    string `xml:"start,attr"`
+	Finish   string `xml:"finish,attr"`
+}
+
+type trxWriteUnitTestResult struct {
+	ExecutionID string          `xml:"executionId,attr"`
+	TestID      string          `xml:"testId,attr"`
+	TestName    string          `xml:"testName,attr"`
+	Duration    string          `xml:"duration,attr,omitempty"`
+	StartTime   string          `xml:"startTime,attr,omitempty"`
+	EndTime     string          `xml:"endTime,attr,omitempty"`
+	TestType    string          `xml:"testType,attr"`
+	Outcome     string          `xml:"outcome,attr"`
+	TestListID  string          `xml:"testListId,attr"`
+	Output      *trxWriteOutput `xml:"Output,omitempty"`
+}
+
+type trxWriteOutput struct {
+	StdOut    string             `xml:"StdOut,omitempty"`
+	ErrorInfo *trxWriteErrorInfo `xml:"ErrorInfo,omitempty"`
+}
+
+type trxWriteErrorInfo struct {
+	Message string `xml:"Message"`
+}
+
+type trxWriteUnitTest struct {
+	Name      string `xml:"name,attr"`
+	ID        string `xml:"id,attr"`
+	Execution struct {
+		ID string `xml:"id,attr"`
+	} `xml:"Execution"`
+	TestMethod struct {
+		CodeBase        string `xml:"codeBase,attr"`
+		AdapterTypeName string `xml:"adapterTypeName,attr"`
+		ClassName       string `xml:"className,attr"`
+		Name            string `xml:"name,attr"`
+	} `xml:"TestMethod"`
+}
+
+type trxWriteTestEntry struct {
+	TestID      string `xml:"testId,attr"`
+	ExecutionID string `xml:"executionId,attr"`
+	TestListID  string `xml:"testListId,attr"`
+}
+
+type trxWriteTestList struct {
+	Name string `xml:"name,attr"`
+	ID   string `xml:"id,attr"`
+}
+
+type trxWriteResultSummary struct {
+	Outcome  string `xml:"outcome,attr"`
+	Counters struct {
+		Total       uint `xml:"total,attr"`
+		Executed    uint `xml:"executed,attr"`
+		Passed      uint `xml:"passed,attr"`
+		Failed      uint `xml:"failed,attr"`
+		NotExecuted uint `xml:"notExecuted,attr"`
+	} `xml:"Counters"`
+}
+
+// WriteTRX writes the test results as a Visual Studio test results (TRX)
+// file. Test and execution IDs are derived from the test names, so the output
+// is deterministic.
+func WriteTRX(w io.Writer, res Result) error {
+	run := trxWriteTestRun{
+		ID:   trxGUID("run", res.Summary.FileName),
+		Name: "wharf-api-client-go",
+		TestLists: []trxWriteTestList{
+			{Name: "Results Not in a List", ID: trxResultsNotInAListID},
+			{Name: "All Loaded Results", ID: trxAllLoadedResultsID},
+		},
+	}
+	var start, finish time.Time
+	for i, d := range res.Details {
+		testID := trxGUID("test", d.Name)
+		executionID := trxGUID("execution", fmt.Sprintf("%d:%s", i, d.Name))
+		result := trxWriteUnitTestResult{
+			ExecutionID: executionID,
+			TestID:      testID,
+			TestName:    d.Name,
+			TestType:    trxUnitTestType,
+			Outcome:     statusToTRXOutcome(d.Status),
+			TestListID:  trxResultsNotInAListID,
+		}
+		if d.StartedOn.Valid {
+			result.StartTime = d.StartedOn.Time.Format(trxTimeLayout)
+			if start.IsZero() || d.StartedOn.Time.Before(start) {
+				start = d.StartedOn.Time
+			}
+		}
+		if d.CompletedOn.Valid {
+			result.EndTime = d.CompletedOn.Time.Format(trxTimeLayout)
+			if d.CompletedOn.Time.After(finish) {
+				finish = d.CompletedOn.Time
+			}
+		}
+		if d.StartedOn.Valid && d.CompletedOn.Valid {
+			result.Duration = formatTRXDuration(d.CompletedOn.Time.Sub(d.StartedOn.Time))
+		}
+		if d.Message.Valid && d.Message.String != "" {
+			if d.Status == response.TestResultStatusFailed {
+				result.Output = &trxWriteOutput{
+					ErrorInfo: &trxWriteErrorInfo{Message: d.Message.String},
+				}
+			} else {
+				result.Output = &trxWriteOutput{StdOut: d.Message.String}
+			}
+		}
+		run.Results = append(run.Results, result)
+
+		def := trxWriteUnitTest{Name: d.Name, ID: testID}
+		def.Execution.ID = executionID
+		def.TestMethod.ClassName, def.TestMethod.Name = splitTestName(d.Name)
+		def.TestMethod.AdapterTypeName = "executor://wharf-api-client-go"
+		run.TestDefinitions = append(run.TestDefinitions, def)
+
+		run.TestEntries = append(run.TestEntries, trxWriteTestEntry{
+			TestID:      testID,
+			ExecutionID: executionID,
+			TestListID:  trxResultsNotInAListID,
+		})
+	}
+	if !start.IsZero() && !finish.IsZero() {
+		run.Times = &trxWriteTimes{
+			Creation: start.Format(trxTimeLayout),
+			Start:    start.Format(trxTimeLayout),
+			Finish:   finish.Format(trxTimeLayout),
+		}
+	}
+	summary := Summarize(res.Details)
+	run.ResultSummary.Outcome = "Completed"
+	if summary.Failed > 0 {
+		run.ResultSummary.Outcome = "Failed"
+	}
+	run.ResultSummary.Counters.Total = summary.Total
+	run.ResultSummary.Counters.Executed = summary.Passed + summary.Failed
+	run.ResultSummary.Counters.Passed = summary.Passed
+	run.ResultSummary.Counters.Failed = summary.Failed
+	run.ResultSummary.Counters.NotExecuted = summary.Skipped
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(run); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// ConvertToTRX parses test results of the given format and writes them as a
+// Visual Studio test results (TRX) file.
+func ConvertToTRX(w io.Writer, format Format, r io.Reader) error {
+	res, err := Parse(format, r)
+	if err != nil {
+		return err
+	}
+	return WriteTRX(w, res)
+}
+
+func statusToTRXOutcome(status response.TestResultStatus) string {
+	switch status {
+	case response.TestResultStatusSuccess:
+		return "Passed"
+	case response.TestResultStatusSkipped:
+		return "NotExecuted"
+	default:
+		return "Failed"
+	}
+}
+
+func formatTRXDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	hours := d / time.Hour
+	d -= hours * time.Hour
+	minutes := d / time.Minute
+	d -= minutes * time.Minute
+	seconds := d / time.Second
+	d -= seconds * time.Second
+	// TRX uses 100-nanosecond ticks.
+	ticks := d / 100
+	return fmt.Sprintf("%02d:%02d:%02d.%07d", hours, minutes, seconds, ticks)
+}
+
+// splitTestName splits a test name such as "Foo.Bar.TestMoo" into a class
+// name and method name, such as "Foo.Bar" and "TestMoo".
+func splitTestName(name string) (className, methodName string) {
+	idx := strings.LastIndexByte(name, '.')
+	if idx == -1 {
+		return "", name
+	}
+	return name[:idx], name[idx+1:]
+}
+
+// trxGUID creates a deterministic name-based GUID, similar to UUID version 5.
+func trxGUID(namespace, name string) string {
+	sum := sha1.Sum([]byte(namespace + "\x00" + name))
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
@@ -0,0 +1,61 @@
+package testresult
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTRX_roundTrip(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		format Format
+		input  string
+	}{
+		{name: "go test -json", format: FormatGoTestJSON, input: testGoTestJSON},
+		{name: "junit", format: FormatJUnit, input: testJUnit},
+		{name: "trx", format: FormatTRX, input: testTRX},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			want, err := Parse(tc.format, strings.NewReader(tc.input))
+			require.NoError(t, err)
+
+			var buf bytes.Buffer
+			require.NoError(t, ConvertToTRX(&buf, tc.format, strings.NewReader(tc.input)))
+			got, err := ParseTRX(&buf)
+			require.NoError(t, err)
+
+			assert.Equal(t, want.Summary, got.Summary)
+			require.Len(t, got.Details, len(want.Details))
+			for i := range want.Details {
+				w, g := want.Details[i], got.Details[i]
+				assert.Equal(t, w.Name, g.Name)
+				assert.Equal(t, w.Status, g.Status)
+				assert.Equal(t, w.Message, g.Message, w.Name)
+				assert.Equal(t, w.StartedOn.Valid, g.StartedOn.Valid, w.Name)
+				if w.StartedOn.Valid {
+					assert.True(t, w.StartedOn.Time.Equal(g.StartedOn.Time), w.Name)
+				}
+				if w.CompletedOn.Valid && w.StartedOn.Valid {
+					assert.True(t, w.CompletedOn.Time.Equal(g.CompletedOn.Time), w.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatTRXDuration(t *testing.T) {
+	d := time.Hour + 2*time.Minute + 3*time.Second + 4500*time.Microsecond
+	assert.Equal(t, "01:02:03.0045000", formatTRXDuration(d))
+}
+
+func TestConvertToTRX_junitWithoutTimestamp(t *testing.T) {
+	input := `<testsuite name="x"><testcase classname="Foo" name="bar" time="1.5"/></testsuite>`
+	var buf bytes.Buffer
+	require.NoError(t, ConvertToTRX(&buf, FormatJUnit, strings.NewReader(input)))
+	assert.Contains(t, buf.String(), `duration="00:00:01.5000000"`)
+}
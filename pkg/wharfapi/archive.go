@@ -0,0 +1,176 @@
+package wharfapi
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// ArchiveFormat is an enum of archive formats that a directory can be packed
+// as when uploading it as a single artifact.
+type ArchiveFormat string
+
+const (
+	// ArchiveNone means the directory is not packed, and each file is instead
+	// uploaded as a separate artifact.
+	ArchiveNone ArchiveFormat = ""
+	// ArchiveTarGz packs the directory as a gzip-compressed tarball.
+	ArchiveTarGz ArchiveFormat = "tar.gz"
+	// ArchiveZip packs the directory as a zip archive.
+	ArchiveZip ArchiveFormat = "zip"
+)
+
+// Ext returns the file extension of the archive format, including the leading
+// dot, or an empty string for ArchiveNone or unknown formats.
+func (f ArchiveFormat) Ext() string {
+	switch f {
+	case ArchiveTarGz:
+		return ".tar.gz"
+	case ArchiveZip:
+		return ".zip"
+	default:
+		return ""
+	}
+}
+
+type dirEntry struct {
+	path    string
+	relPath string
+	info    fs.FileInfo
+}
+
+// walkDirFiles returns all regular files inside the directory, in lexical
+// order.
+func walkDirFiles(dir string) ([]dirEntry, error) {
+	var entries []dirEntry
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, dirEntry{
+			path:    path,
+			relPath: filepath.ToSlash(rel),
+			info:    info,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walk directory %q: %w", dir, err)
+	}
+	return entries, nil
+}
+
+func listDirFiles(dir string) ([]file, error) {
+	entries, err := walkDirFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]file, 0, len(entries))
+	for _, entry := range entries {
+		path := entry.path
+		files = append(files, file{
+			fieldName: "files",
+			fileName:  entry.relPath,
+			size:      entry.info.Size(),
+			open: func() (io.ReadCloser, error) {
+				return os.Open(path)
+			},
+		})
+	}
+	return files, nil
+}
+
+func newArchiveFile(dir, fileName string, format ArchiveFormat) (file, error) {
+	var write func(io.Writer, []dirEntry) error
+	switch format {
+	case ArchiveTarGz:
+		write = writeTarGz
+	case ArchiveZip:
+		write = writeZip
+	default:
+		return file{}, fmt.Errorf("unsupported archive format: %q", format)
+	}
+	entries, err := walkDirFiles(dir)
+	if err != nil {
+		return file{}, err
+	}
+	return file{
+		fieldName: "files",
+		fileName:  fileName,
+		open: func() (io.ReadCloser, error) {
+			r, w := io.Pipe()
+			go func() {
+				w.CloseWithError(write(w, entries))
+			}()
+			return r, nil
+		},
+	}, nil
+}
+
+func writeTarGz(w io.Writer, entries []dirEntry) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	for _, entry := range entries {
+		hdr, err := tar.FileInfoHeader(entry.info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = entry.relPath
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if err := copyFileTo(tw, entry.path); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func writeZip(w io.Writer, entries []dirEntry) error {
+	zw := zip.NewWriter(w)
+	for _, entry := range entries {
+		hdr, err := zip.FileInfoHeader(entry.info)
+		if err != nil {
+			return err
+		}
+		hdr.Name = entry.relPath
+		hdr.Method = zip.Deflate
+		fw, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		if err := copyFileTo(fw, entry.path); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func copyFileTo(w io.Writer, path string) (finalErr error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer closeAndSetError(f, &finalErr)
+	_, finalErr = io.Copy(w, f)
+	return
+}
@@ -1,9 +1,11 @@
 package wharfapi
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
+	"path/filepath"
 
 	"github.com/google/go-querystring/query"
 	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
@@ -43,6 +45,28 @@ func (c *Client) GetBuildArtifactList(params ArtifactSearch, buildID uint) (resp
 	return artifacts, err
 }
 
+// GetBuildArtifactListAll fetches all artifacts matching the parameters by
+// repeatedly invoking the HTTP request:
+//  GET /api/build/{buildId}/artifact
+//
+// The ArtifactSearch.Limit field is used as page size, and defaults to 100.
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) GetBuildArtifactListAll(params ArtifactSearch, buildID uint) ([]response.Artifact, error) {
+	var artifacts []response.Artifact
+	err := fetchAllPages(params.Limit, params.Offset, func(limit, offset int) (int, int64, error) {
+		params.Limit = &limit
+		params.Offset = &offset
+		page, err := c.GetBuildArtifactList(params, buildID)
+		if err != nil {
+			return 0, 0, err
+		}
+		artifacts = append(artifacts, page.List...)
+		return len(page.List), page.TotalCount, nil
+	})
+	return artifacts, err
+}
+
 // GetBuildArtifact gets an artifact by invoking the HTTP request:
 //  GET /api/build/{buildId}/artifact/{artifactId}
 //
@@ -55,6 +79,24 @@ func (c *Client) GetBuildArtifact(buildID, artifactID uint) (io.ReadCloser, erro
 	return c.get(path, nil)
 }
 
+// DownloadBuildArtifact downloads an artifact to a file by invoking the HTTP
+// request:
+//  GET /api/build/{buildId}/artifact/{artifactId}
+//
+// The artifact is first written to a temporary file next to the destination,
+// with the ".part" suffix, and then renamed to the destination once fully
+// downloaded, so the destination file is never partially written. See
+// ArtifactDownloadOptions for resuming and checksum settings.
+//
+// Added in wharf-api v0.7.1.
+func (c *Client) DownloadBuildArtifact(ctx context.Context, buildID, artifactID uint, dst string, opts ArtifactDownloadOptions) (ArtifactDownload, error) {
+	if err := c.validateEndpointVersion(0, 7, 1); err != nil {
+		return ArtifactDownload{}, err
+	}
+	path := fmt.Sprintf("/api/build/%d/artifact/%d", buildID, artifactID)
+	return c.downloadToFile(ctx, path, dst, opts)
+}
+
 // CreateBuildArtifact uploads an artifact by invoking the HTTP request:
 //  POST /api/build/{buildId}/artifact
 //
@@ -64,14 +106,115 @@ func (c Client) CreateBuildArtifact(buildID uint, fileName string, artifact io.R
 		return err
 	}
 	path := fmt.Sprintf("/api/build/%d/artifact", buildID)
-	resp, err := c.uploadMultipart(http.MethodPost, path, map[string]file{
-		"files": {
-			fileName: fileName,
-			reader:   artifact,
-		},
-	})
+	resp, err := c.uploadMultipart(context.Background(), http.MethodPost, path, []file{
+		newReaderFile("files", fileName, 0, artifact),
+	}, nil)
 	if err != nil {
 		return err
 	}
 	return resp.Close()
 }
+
+// ArtifactFile is a single file to be uploaded as a build artifact.
+type ArtifactFile struct {
+	FileName string
+	Reader   io.Reader
+	// Size is the number of bytes that will be read from the Reader, and is
+	// only used in progress reports. Leave at zero if unknown.
+	Size int64
+}
+
+// UploadProgress is a progress report of a single file that is being
+// uploaded.
+type UploadProgress struct {
+	FileName  string
+	BytesSent int64
+	// TotalBytes is the total size of the file, or -1 if unknown.
+	TotalBytes int64
+	// Done is true on the last progress report of the file.
+	Done bool
+}
+
+// UploadProgressFunc is a callback used to receive progress reports during
+// uploads. It is called from the goroutine that is writing the request body.
+type UploadProgressFunc func(UploadProgress)
+
+// ArtifactUploadOptions holds optional settings used when uploading
+// artifacts.
+type ArtifactUploadOptions struct {
+	// Progress, if set, is called every time bytes has been sent, and a final
+	// time when a file has been fully sent.
+	Progress UploadProgressFunc
+}
+
+// CreateBuildArtifactList uploads multiple artifacts in a single request by
+// invoking the HTTP request:
+//  POST /api/build/{buildId}/artifact
+//
+// The files are sent in the order they are given. Cancelling the context
+// aborts the upload.
+//
+// Added in wharf-api v0.4.9.
+func (c *Client) CreateBuildArtifactList(ctx context.Context, buildID uint, files []ArtifactFile, opts ArtifactUploadOptions) ([]response.ArtifactMetadata, error) {
+	multipartFiles := make([]file, 0, len(files))
+	for _, f := range files {
+		multipartFiles = append(multipartFiles,
+			newReaderFile("files", f.FileName, f.Size, f.Reader))
+	}
+	return c.createBuildArtifactList(ctx, buildID, multipartFiles, opts)
+}
+
+// ArtifactDirOptions holds optional settings used when uploading a directory
+// of artifacts.
+type ArtifactDirOptions struct {
+	ArtifactUploadOptions
+	// Archive, if set, packs the whole directory into a single artifact using
+	// the given archive format instead of uploading each file separately.
+	Archive ArchiveFormat
+	// ArchiveName is the file name of the archive artifact. Defaults to the
+	// directory's base name with the archive format's file extension.
+	ArchiveName string
+}
+
+// CreateBuildArtifactDir uploads all files found recursively inside a
+// directory by invoking the HTTP request:
+//  POST /api/build/{buildId}/artifact
+//
+// Files are uploaded in lexical order, using their slash-separated path
+// relative to the directory as file name, unless the ArtifactDirOptions.Archive
+// field is set, where the directory is instead packed and streamed as a
+// single archive artifact. Cancelling the context aborts the upload.
+//
+// Added in wharf-api v0.4.9.
+func (c *Client) CreateBuildArtifactDir(ctx context.Context, buildID uint, dir string, opts ArtifactDirOptions) ([]response.ArtifactMetadata, error) {
+	if opts.Archive != ArchiveNone {
+		name := opts.ArchiveName
+		if name == "" {
+			name = filepath.Base(filepath.Clean(dir)) + opts.Archive.Ext()
+		}
+		archive, err := newArchiveFile(dir, name, opts.Archive)
+		if err != nil {
+			return nil, err
+		}
+		return c.createBuildArtifactList(ctx, buildID, []file{archive}, opts.ArtifactUploadOptions)
+	}
+	files, err := listDirFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	return c.createBuildArtifactList(ctx, buildID, files, opts.ArtifactUploadOptions)
+}
+
+func (c *Client) createBuildArtifactList(ctx context.Context, buildID uint, files []file, opts ArtifactUploadOptions) ([]response.ArtifactMetadata, error) {
+	if err := c.validateEndpointVersion(0, 4, 9); err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("/api/build/%d/artifact", buildID)
+	body, err := c.uploadMultipart(ctx, http.MethodPost, path, files, opts.Progress)
+	if err != nil {
+		return nil, err
+	}
+	var artifacts []response.ArtifactMetadata
+	err = c.decodeResponse(body, &artifacts)
+	return artifacts, err
+}
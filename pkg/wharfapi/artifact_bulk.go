@@ -0,0 +1,197 @@
+package wharfapi
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+)
+
+// defaultDownloadConcurrency is the number of concurrent downloads used when
+// downloading multiple artifacts, unless specified otherwise.
+const defaultDownloadConcurrency = 4
+
+// BuildArtifactsDownloadOptions holds optional settings used when downloading
+// all artifacts of a build.
+type BuildArtifactsDownloadOptions struct {
+	// ArtifactDownloadOptions are applied to each individual download. Note
+	// that the Progress callback is called concurrently from multiple
+	// goroutines.
+	ArtifactDownloadOptions
+	// Search filters which artifacts to download, such as via its Name,
+	// FileName, NameMatch, or FileNameMatch fields.
+	Search ArtifactSearch
+	// Concurrency is the maximum number of concurrent downloads. Defaults to 4.
+	Concurrency int
+}
+
+// ArtifactManifest lists the artifacts fetched when downloading all artifacts
+// of a build.
+type ArtifactManifest struct {
+	BuildID   uint                    `json:"buildId"`
+	Dir       string                  `json:"dir"`
+	Artifacts []ArtifactManifestEntry `json:"artifacts"`
+}
+
+// ArtifactManifestEntry is a single artifact in an ArtifactManifest.
+type ArtifactManifestEntry struct {
+	response.Artifact
+	// Path is the slash-separated path of the downloaded file, relative to the
+	// ArtifactManifest.Dir directory. It is based on the artifact's file name,
+	// but suffixed with the artifact ID on name collisions.
+	Path string `json:"path"`
+	// Size is the size of the downloaded file.
+	Size int64 `json:"size"`
+	// SHA256 is the hex-encoded SHA-256 checksum of the downloaded file, if
+	// enabled via the ArtifactDownloadOptions.SHA256 field.
+	SHA256 string `json:"sha256,omitempty"`
+	// Error is the error message if the download failed.
+	Error string `json:"error,omitempty"`
+}
+
+// DownloadBuildArtifactList downloads all artifacts of a build into a
+// directory by fetching the list of artifacts and then downloading them
+// concurrently, by invoking the HTTP requests:
+//  GET /api/build/{buildId}/artifact
+//  GET /api/build/{buildId}/artifact/{artifactId}
+//
+// A failed download does not stop the other downloads. The returned manifest
+// contains all artifacts, including failed ones, together with an error
+// if any of the downloads failed.
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) DownloadBuildArtifactList(ctx context.Context, buildID uint, dir string, opts BuildArtifactsDownloadOptions) (ArtifactManifest, error) {
+	manifest := ArtifactManifest{
+		BuildID: buildID,
+		Dir:     dir,
+	}
+	// Validating once here, as the version check is not safe for concurrent
+	// use.
+	if err := c.validateEndpointVersion(5, 0, 0); err != nil {
+		return manifest, err
+	}
+	artifacts, err := c.GetBuildArtifactListAll(opts.Search, buildID)
+	if err != nil {
+		return manifest, fmt.Errorf("list artifacts: %w", err)
+	}
+	manifest.Artifacts = newArtifactManifestEntries(artifacts)
+	if len(manifest.Artifacts) == 0 {
+		return manifest, nil
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultDownloadConcurrency
+	}
+	indices := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency && i < len(manifest.Artifacts); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range indices {
+				entry := &manifest.Artifacts[idx]
+				c.downloadManifestEntry(ctx, buildID, dir, entry, opts.ArtifactDownloadOptions)
+			}
+		}()
+	}
+	for idx := range manifest.Artifacts {
+		if ctx.Err() != nil {
+			break
+		}
+		indices <- idx
+	}
+	close(indices)
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return manifest, ctx.Err()
+	}
+	var failed int
+	var firstErr string
+	for _, entry := range manifest.Artifacts {
+		if entry.Error != "" {
+			if failed == 0 {
+				firstErr = entry.Error
+			}
+			failed++
+		}
+	}
+	if failed > 0 {
+		return manifest, fmt.Errorf("download artifacts: %d of %d failed, first error: %s",
+			failed, len(manifest.Artifacts), firstErr)
+	}
+	return manifest, nil
+}
+
+func (c *Client) downloadManifestEntry(ctx context.Context, buildID uint, dir string, entry *ArtifactManifestEntry, opts ArtifactDownloadOptions) {
+	dst := filepath.Join(dir, filepath.FromSlash(entry.Path))
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		entry.Error = err.Error()
+		return
+	}
+	path := fmt.Sprintf("/api/build/%d/artifact/%d", buildID, entry.ArtifactID)
+	download, err := c.downloadToFile(ctx, path, dst, opts)
+	if err != nil {
+		entry.Error = err.Error()
+		return
+	}
+	entry.Size = download.BytesWritten
+	entry.SHA256 = download.SHA256
+}
+
+// newArtifactManifestEntries assigns unique relative file paths to the
+// artifacts. The artifacts are sorted by ID so that the oldest artifact gets
+// to keep its file name in case of collisions. Paths are compared
+// case-insensitively, so they do not overwrite each other on case-insensitive
+// file systems.
+func newArtifactManifestEntries(artifacts []response.Artifact) []ArtifactManifestEntry {
+	sorted := make([]response.Artifact, len(artifacts))
+	copy(sorted, artifacts)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].ArtifactID < sorted[j].ArtifactID
+	})
+	entries := make([]ArtifactManifestEntry, 0, len(sorted))
+	used := make(map[string]struct{}, len(sorted))
+	for _, artifact := range sorted {
+		p := artifactFilePath(artifact)
+		ext := path.Ext(p)
+		base := strings.TrimSuffix(p, ext)
+		for n := 1; isStringInSet(used, strings.ToLower(p)); n++ {
+			if n == 1 {
+				p = fmt.Sprintf("%s-%d%s", base, artifact.ArtifactID, ext)
+			} else {
+				p = fmt.Sprintf("%s-%d-%d%s", base, artifact.ArtifactID, n, ext)
+			}
+		}
+		used[strings.ToLower(p)] = struct{}{}
+		entries = append(entries, ArtifactManifestEntry{
+			Artifact: artifact,
+			Path:     p,
+		})
+	}
+	return entries
+}
+
+// artifactFilePath returns a slash-separated relative file path based on the
+// artifact's file name that is guaranteed to not escape the target directory.
+func artifactFilePath(artifact response.Artifact) string {
+	name := strings.ReplaceAll(artifact.FileName, "\\", "/")
+	// Cleaning it as an absolute path removes any leading "../" elements.
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	if name == "" {
+		return fmt.Sprintf("artifact-%d", artifact.ArtifactID)
+	}
+	return name
+}
+
+func isStringInSet(set map[string]struct{}, s string) bool {
+	_, ok := set[s]
+	return ok
+}
@@ -0,0 +1,99 @@
+package wharfapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewArtifactManifestEntries(t *testing.T) {
+	artifacts := []response.Artifact{
+		{ArtifactID: 3, FileName: "a.txt"},
+		{ArtifactID: 1, FileName: "a.txt"},
+		{ArtifactID: 2, FileName: "../../etc/passwd"},
+		{ArtifactID: 4, FileName: ""},
+		{ArtifactID: 5, FileName: `sub\b.tar.gz`},
+		{ArtifactID: 6, FileName: "a-3.txt"},
+		{ArtifactID: 7, FileName: "A.txt"},
+	}
+	entries := newArtifactManifestEntries(artifacts)
+	var got []string
+	for _, e := range entries {
+		got = append(got, fmt.Sprintf("%d:%s", e.ArtifactID, e.Path))
+	}
+	want := []string{
+		"1:a.txt",
+		"2:etc/passwd",
+		"3:a-3.txt",
+		"4:artifact-4",
+		"5:sub/b.tar.gz",
+		"6:a-3-6.txt",
+		"7:A-7.txt",
+	}
+	assert.Equal(t, want, got)
+}
+
+func TestDownloadBuildArtifactList(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/build/1/artifact":
+			assert.Equal(t, "*.txt", r.URL.Query().Get("fileNameMatch"))
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"totalCount":2,"list":[
+				{"artifactId":10,"fileName":"a.txt"},
+				{"artifactId":11,"fileName":"sub/b.txt"}]}`)
+		case "/api/build/1/artifact/10":
+			fmt.Fprint(w, "aaa")
+		case "/api/build/1/artifact/11":
+			fmt.Fprint(w, "bb")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+	dir := t.TempDir()
+
+	match := "*.txt"
+	manifest, err := c.DownloadBuildArtifactList(context.Background(), 1, dir, BuildArtifactsDownloadOptions{
+		Search: ArtifactSearch{FileNameMatch: &match},
+	})
+	require.NoError(t, err)
+	require.Len(t, manifest.Artifacts, 2)
+	assert.Equal(t, "sub/b.txt", manifest.Artifacts[1].Path)
+	assert.Equal(t, int64(2), manifest.Artifacts[1].Size)
+
+	b, err := os.ReadFile(filepath.Join(dir, "sub", "b.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "bb", string(b))
+}
+
+func TestDownloadBuildArtifactList_partialFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/api/build/1/artifact":
+			fmt.Fprint(w, `{"totalCount":2,"list":[{"artifactId":10,"fileName":"a.txt"},{"artifactId":11,"fileName":"b.txt"}]}`)
+		case strings.HasSuffix(r.URL.Path, "/10"):
+			fmt.Fprint(w, "aaa")
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+
+	manifest, err := c.DownloadBuildArtifactList(context.Background(), 1, t.TempDir(), BuildArtifactsDownloadOptions{})
+	require.Error(t, err)
+	require.Len(t, manifest.Artifacts, 2)
+	assert.Empty(t, manifest.Artifacts[0].Error)
+	assert.NotEmpty(t, manifest.Artifacts[1].Error)
+}
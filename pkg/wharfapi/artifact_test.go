@@ -0,0 +1,140 @@
+package wharfapi
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testUploadedFile struct {
+	fileName string
+	content  string
+}
+
+func newTestUploadServer(t *testing.T, uploaded *[]testUploadedFile) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mr, err := r.MultipartReader()
+		if !assert.NoError(t, err) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if !assert.NoError(t, err) {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			assert.Equal(t, "files", part.FormName())
+			b, err := io.ReadAll(part)
+			assert.NoError(t, err)
+			// part.FileName() strips directories, so parse it ourselves
+			_, params, err := mime.ParseMediaType(part.Header.Get("Content-Disposition"))
+			assert.NoError(t, err)
+			*uploaded = append(*uploaded, testUploadedFile{params["filename"], string(b)})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `[{"artifactId":1,"fileName":"a.txt"}]`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCreateBuildArtifactList(t *testing.T) {
+	var uploaded []testUploadedFile
+	srv := newTestUploadServer(t, &uploaded)
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+
+	var reports []UploadProgress
+	artifacts, err := c.CreateBuildArtifactList(context.Background(), 1, []ArtifactFile{
+		{FileName: "b.txt", Reader: strings.NewReader("bbb"), Size: 3},
+		{FileName: "a.txt", Reader: strings.NewReader("a")},
+	}, ArtifactUploadOptions{
+		Progress: func(p UploadProgress) { reports = append(reports, p) },
+	})
+	require.NoError(t, err)
+	require.Len(t, artifacts, 1)
+
+	want := []testUploadedFile{{"b.txt", "bbb"}, {"a.txt", "a"}}
+	assert.Equal(t, want, uploaded)
+
+	require.NotEmpty(t, reports)
+	last := reports[len(reports)-1]
+	assert.Equal(t, UploadProgress{FileName: "a.txt", BytesSent: 1, TotalBytes: -1, Done: true}, last)
+	assert.Contains(t, reports, UploadProgress{FileName: "b.txt", BytesSent: 3, TotalBytes: 3, Done: true})
+}
+
+func TestCreateBuildArtifactList_cancelled(t *testing.T) {
+	var uploaded []testUploadedFile
+	srv := newTestUploadServer(t, &uploaded)
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := c.CreateBuildArtifactList(ctx, 1, []ArtifactFile{
+		{FileName: "a.txt", Reader: strings.NewReader("a")},
+	}, ArtifactUploadOptions{})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCreateBuildArtifactDir(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.txt"), []byte("bbb"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "a.txt"), []byte("a"), 0644))
+
+	var uploaded []testUploadedFile
+	srv := newTestUploadServer(t, &uploaded)
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+
+	_, err := c.CreateBuildArtifactDir(context.Background(), 1, dir, ArtifactDirOptions{})
+	require.NoError(t, err)
+	want := []testUploadedFile{{"b.txt", "bbb"}, {"sub/a.txt", "a"}}
+	assert.Equal(t, want, uploaded)
+}
+
+func TestCreateBuildArtifactDir_tarGz(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "sub"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.txt"), []byte("bbb"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "sub", "a.txt"), []byte("a"), 0644))
+
+	var uploaded []testUploadedFile
+	srv := newTestUploadServer(t, &uploaded)
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+
+	_, err := c.CreateBuildArtifactDir(context.Background(), 1, dir, ArtifactDirOptions{
+		Archive:     ArchiveTarGz,
+		ArchiveName: "out.tar.gz",
+	})
+	require.NoError(t, err)
+	require.Len(t, uploaded, 1)
+	assert.Equal(t, "out.tar.gz", uploaded[0].fileName)
+
+	gr, err := gzip.NewReader(strings.NewReader(uploaded[0].content))
+	require.NoError(t, err)
+	tr := tar.NewReader(gr)
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, hdr.Name)
+	}
+	assert.Equal(t, []string{"b.txt", "sub/a.txt"}, names)
+}
@@ -1,6 +1,7 @@
 package wharfapi
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/request"
@@ -32,6 +33,10 @@ func (c *Client) UpdateProjectBranchList(projectID uint, branches []request.Bran
 	if err := c.validateEndpointVersion(5, 0, 0); err != nil {
 		return nil, err
 	}
+	return c.updateProjectBranchList(context.Background(), projectID, branches)
+}
+
+func (c *Client) updateProjectBranchList(ctx context.Context, projectID uint, branches []request.Branch) ([]response.Branch, error) {
 	body := request.BranchListUpdate{
 		Branches: make([]request.BranchUpdate, 0, len(branches)),
 	}
@@ -45,7 +50,7 @@ func (c *Client) UpdateProjectBranchList(projectID uint, branches []request.Bran
 	}
 	var response response.BranchList
 	path := fmt.Sprintf("/api/project/%d/branch", projectID)
-	err := c.putJSONUnmarshal(path, nil, body, &response)
+	err := c.putJSONUnmarshalContext(ctx, path, nil, body, &response)
 	return response.Branches, err
 }
 
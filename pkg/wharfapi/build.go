@@ -1,6 +1,7 @@
 package wharfapi
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
@@ -126,11 +127,33 @@ func (c *Client) GetBuildLogList(buildID uint) ([]response.Log, error) {
 // StartProjectBuild starts a new build by invoking the HTTP request:
 //  POST /api/project/{projectID}/build
 //
+// If the Client.ValidateEngine flag is enabled, then the ProjectStartBuild.Engine
+// field is first resolved using Client.ResolveEngine, so that engine names
+// are translated to their IDs and unknown engines are reported before the
+// build is started.
+//
 // Added in wharf-api v5.0.0.
 func (c *Client) StartProjectBuild(projectID uint, params ProjectStartBuild, inputs request.BuildInputs) (response.BuildReferenceWrapper, error) {
 	if err := c.validateEndpointVersion(5, 0, 0); err != nil {
 		return response.BuildReferenceWrapper{}, err
 	}
+	if c.ValidateEngine {
+		engine, err := c.ResolveEngine(params.Engine)
+		switch {
+		case errors.Is(err, ErrNoDefaultEngine):
+			// Let wharf-api decide, as it may still have a fallback.
+		case err != nil:
+			return response.BuildReferenceWrapper{}, err
+		default:
+			log.Debug().
+				WithString("engine", engine.ID).
+				WithString("engineName", engine.Name).
+				Message("Resolved engine for new build.")
+			if params.Engine != "" {
+				params.Engine = engine.ID
+			}
+		}
+	}
 	var newBuildRef response.BuildReferenceWrapper
 	q, err := query.Values(params)
 	if err != nil {
@@ -1,6 +1,7 @@
 package wharfapi
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,7 +11,9 @@ import (
 	"net/url"
 
 	"github.com/blang/semver/v4"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
 	"github.com/iver-wharf/wharf-core/pkg/logger"
+	"google.golang.org/grpc"
 )
 
 // AuthError is returned on authentication/authorization errors issued when
@@ -43,6 +46,15 @@ type Client struct {
 	AuthHeader string
 	APIURL     string
 
+	// HTTPClient is used to send the HTTP requests, such as to use a custom
+	// http.RoundTripper. A new http.Client is used if nil.
+	HTTPClient *http.Client
+
+	// GRPCDialOptions are added last when dialing the wharf-api gRPC server,
+	// so they take precedence over the default options. Can be used to dial
+	// using a custom dialer, such as an in-process listener in tests.
+	GRPCDialOptions []grpc.DialOption
+
 	// ErrIfOutdatedClient will error if the client is outdated. Wharf aims
 	// for a backward compatability of 1 major version back, so the client will
 	// only prematurely error before making a request if the client is 2 major
@@ -65,10 +77,26 @@ type Client struct {
 	// server.
 	DisableOutdatedLogging bool
 
+	// ValidateEngine will resolve the engine ID or name given when starting
+	// new builds against the list of execution engines from the wharf-api
+	// before even making the web request. The list of engines is fetched once
+	// and then cached. See Client.ResolveEngine for details.
+	ValidateEngine bool
+
+	// Redactor removes sensitive values from URLs, headers, and bodies before
+	// they are logged. Uses the DefaultRedactor if nil.
+	Redactor *Redactor
+
+	// HTTPDump enables logging of the status, latency, selected headers, and
+	// truncated bodies of all HTTP requests and responses at debug level,
+	// when set. Useful when debugging misbehaving calls.
+	HTTPDump *HTTPDumpOptions
+
 	hasCheckedVersion             bool
 	hasLoggedClientVersionWarning bool
 	hasLoggedServerVersionWarning bool
 	cachedVersion                 *semver.Version
+	cachedEngineList              *response.EngineList
 }
 
 // HighestSupportedVersion is the highest version that the wharf-api-client-go
@@ -84,77 +112,117 @@ var HighestSupportedVersion = semver.MustParse("5.1.0")
 type WharfClient Client
 
 func (c *Client) get(path string, q url.Values) (io.ReadCloser, error) {
-	req, err := c.newRequest(http.MethodGet, path, q, nil)
+	return c.getContext(context.Background(), path, q)
+}
+
+func (c *Client) getContext(ctx context.Context, path string, q url.Values) (io.ReadCloser, error) {
+	req, err := c.newRequest(ctx, http.MethodGet, path, q, nil)
 	if err != nil {
 		return nil, err
 	}
-	return doRequest(req)
+	return c.doRequest(req)
 }
 
 func (c *Client) getUnmarshal(path string, q url.Values, response interface{}) error {
-	ioBody, err := c.get(path, q)
+	return c.getUnmarshalContext(context.Background(), path, q, response)
+}
+
+func (c *Client) getUnmarshalContext(ctx context.Context, path string, q url.Values, response interface{}) error {
+	ioBody, err := c.getContext(ctx, path, q)
 	if err != nil {
 		return err
 	}
-	return decodeJSONAndClose(ioBody, response)
+	return c.decodeResponse(ioBody, response)
 }
 
 func (c *Client) post(path string, q url.Values, body io.Reader) (io.ReadCloser, error) {
-	req, err := c.newRequest(http.MethodPost, path, q, body)
+	return c.postContext(context.Background(), path, q, body)
+}
+
+func (c *Client) postContext(ctx context.Context, path string, q url.Values, body io.Reader) (io.ReadCloser, error) {
+	req, err := c.newRequest(ctx, http.MethodPost, path, q, body)
 	if err != nil {
 		return nil, err
 	}
-	return doRequest(req)
+	return c.doRequest(req)
 }
 
 func (c *Client) postJSON(path string, q url.Values, request interface{}) (resp io.ReadCloser, finalErr error) {
+	return c.postJSONContext(context.Background(), path, q, request)
+}
+
+func (c *Client) postJSONContext(ctx context.Context, path string, q url.Values, request interface{}) (resp io.ReadCloser, finalErr error) {
+	log.Debug().WithStringer("body", redactedJSON{request, c.redactor()}).Message("Request body.")
 	r := newJSONEncodeReader(request)
 	defer closeAndSetError(r, &finalErr)
-	resp, finalErr = c.post(path, q, r)
+	resp, finalErr = c.postContext(ctx, path, q, r)
 	return
 }
 
 func (c *Client) postJSONUnmarshal(path string, q url.Values, request, response interface{}) error {
-	body, err := c.postJSON(path, q, request)
+	return c.postJSONUnmarshalContext(context.Background(), path, q, request, response)
+}
+
+func (c *Client) postJSONUnmarshalContext(ctx context.Context, path string, q url.Values, request, response interface{}) error {
+	body, err := c.postJSONContext(ctx, path, q, request)
 	if err != nil {
 		return err
 	}
-	return decodeJSONAndClose(body, response)
+	return c.decodeResponse(body, response)
 }
 
 func (c *Client) put(path string, q url.Values, body io.Reader) (io.ReadCloser, error) {
-	req, err := c.newRequest(http.MethodPut, path, q, body)
+	return c.putContext(context.Background(), path, q, body)
+}
+
+func (c *Client) putContext(ctx context.Context, path string, q url.Values, body io.Reader) (io.ReadCloser, error) {
+	req, err := c.newRequest(ctx, http.MethodPut, path, q, body)
 	if err != nil {
 		return nil, err
 	}
-	return doRequest(req)
+	return c.doRequest(req)
 }
 
-func (c *Client) putJSON(path string, q url.Values, request interface{}) (resp io.ReadCloser, finalErr error) {
+func (c *Client) putJSONContext(ctx context.Context, path string, q url.Values, request interface{}) (resp io.ReadCloser, finalErr error) {
+	log.Debug().WithStringer("body", redactedJSON{request, c.redactor()}).Message("Request body.")
 	r := newJSONEncodeReader(request)
 	defer closeAndSetError(r, &finalErr)
-	resp, finalErr = c.put(path, q, r)
+	resp, finalErr = c.putContext(ctx, path, q, r)
 	return
 }
 
 func (c *Client) putJSONUnmarshal(path string, q url.Values, request, response interface{}) error {
-	ioBody, err := c.putJSON(path, q, request)
+	return c.putJSONUnmarshalContext(context.Background(), path, q, request, response)
+}
+
+func (c *Client) putJSONUnmarshalContext(ctx context.Context, path string, q url.Values, request, response interface{}) error {
+	ioBody, err := c.putJSONContext(ctx, path, q, request)
 	if err != nil {
 		return err
 	}
-	return decodeJSONAndClose(ioBody, response)
+	return c.decodeResponse(ioBody, response)
+}
+
+// decodeResponse decodes the JSON response body and logs it with all
+// sensitive values redacted.
+func (c *Client) decodeResponse(r io.ReadCloser, obj interface{}) error {
+	if err := decodeJSONAndClose(r, obj); err != nil {
+		return err
+	}
+	log.Debug().WithStringer("body", redactedJSON{obj, c.redactor()}).Message("Response body.")
+	return nil
 }
 
-func (c *Client) newRequest(method, path string, q url.Values, body io.Reader) (*http.Request, error) {
-	return newRequest(method, c.AuthHeader, c.APIURL, path, q, body)
+func (c *Client) newRequest(ctx context.Context, method, path string, q url.Values, body io.Reader) (*http.Request, error) {
+	return newRequest(ctx, method, c.AuthHeader, c.APIURL, path, q, body)
 }
 
 func (c *Client) delete(path string, q url.Values, body io.Reader) (io.ReadCloser, error) {
-	req, err := c.newRequest(http.MethodDelete, path, q, body)
+	req, err := c.newRequest(context.Background(), http.MethodDelete, path, q, body)
 	if err != nil {
 		return nil, err
 	}
-	return doRequest(req)
+	return c.doRequest(req)
 }
 
 // SetCachedVersion will override the version that the wharf-api-client-go
@@ -273,24 +341,39 @@ func newJSONEncodeReader(obj interface{}) io.ReadCloser {
 }
 
 type file struct {
-	fileName string
-	reader   io.Reader
+	fieldName string
+	fileName  string
+	// size is the number of bytes that will be read, used in progress
+	// reports. Zero or negative means unknown.
+	size int64
+	open func() (io.ReadCloser, error)
 }
 
-func (c Client) uploadMultipart(method, path string, files map[string]file) (resp io.ReadCloser, finalErr error) {
+func newReaderFile(fieldName, fileName string, size int64, r io.Reader) file {
+	return file{
+		fieldName: fieldName,
+		fileName:  fileName,
+		size:      size,
+		open: func() (io.ReadCloser, error) {
+			return io.NopCloser(r), nil
+		},
+	}
+}
+
+func (c Client) uploadMultipart(ctx context.Context, method, path string, files []file, progress UploadProgressFunc) (resp io.ReadCloser, finalErr error) {
 	pipeReader, pipeWriter := io.Pipe()
 	defer closeAndSetError(pipeReader, &finalErr)
 	mw := multipart.NewWriter(pipeWriter)
 
-	go writeMultipartFiles(mw, pipeWriter, files)
+	go writeMultipartFiles(ctx, mw, pipeWriter, files, progress)
 
-	req, err := c.newRequest(method, path, nil, pipeReader)
+	req, err := c.newRequest(ctx, method, path, nil, pipeReader)
 	if err != nil {
 		finalErr = err
 		return
 	}
 	req.Header.Set("Content-Type", mw.FormDataContentType())
-	resp, finalErr = doRequest(req)
+	resp, finalErr = c.doRequest(req)
 	return
 }
 
@@ -299,15 +382,11 @@ type closerWithError interface {
 	CloseWithError(err error) error
 }
 
-func writeMultipartFiles(mw *multipart.Writer, closer closerWithError, files map[string]file) {
-	for field, file := range files {
-		fw, err := mw.CreateFormFile(field, file.fileName)
-		if err != nil {
-			closer.CloseWithError(err)
-			return
-		}
-		_, err = io.Copy(fw, file.reader)
-		if err != nil {
+// writeMultipartFiles writes the files in the order they are given, so that
+// the resulting multipart body is deterministic.
+func writeMultipartFiles(ctx context.Context, mw *multipart.Writer, closer closerWithError, files []file, progress UploadProgressFunc) {
+	for _, f := range files {
+		if err := writeMultipartFile(ctx, mw, f, progress); err != nil {
 			closer.CloseWithError(err)
 			return
 		}
@@ -318,3 +397,70 @@ func writeMultipartFiles(mw *multipart.Writer, closer closerWithError, files map
 	mw.Close()
 	closer.Close()
 }
+
+func writeMultipartFile(ctx context.Context, mw *multipart.Writer, f file, progress UploadProgressFunc) (finalErr error) {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	fw, err := mw.CreateFormFile(f.fieldName, f.fileName)
+	if err != nil {
+		return err
+	}
+	rc, err := f.open()
+	if err != nil {
+		return fmt.Errorf("open %q: %w", f.fileName, err)
+	}
+	defer closeAndSetError(rc, &finalErr)
+	var r io.Reader = contextReader{ctx, rc}
+	var pr *progressReader
+	if progress != nil {
+		pr = &progressReader{
+			reader: r,
+			report: progress,
+			progress: UploadProgress{
+				FileName:   f.fileName,
+				TotalBytes: f.size,
+			},
+		}
+		if pr.progress.TotalBytes <= 0 {
+			pr.progress.TotalBytes = -1
+		}
+		r = pr
+	}
+	if _, err := io.Copy(fw, r); err != nil {
+		return err
+	}
+	if pr != nil {
+		pr.progress.Done = true
+		progress(pr.progress)
+	}
+	return nil
+}
+
+// contextReader aborts reading as soon as the context is cancelled.
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (r contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.reader.Read(p)
+}
+
+type progressReader struct {
+	reader   io.Reader
+	report   UploadProgressFunc
+	progress UploadProgress
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		r.progress.BytesSent += int64(n)
+		r.report(r.progress)
+	}
+	return n, err
+}
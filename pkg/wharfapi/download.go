@@ -0,0 +1,376 @@
+package wharfapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DownloadProgress is a progress report of a single file that is being
+// downloaded.
+type DownloadProgress struct {
+	FileName      string
+	BytesReceived int64
+	// TotalBytes is the total size of the file, or -1 if unknown.
+	TotalBytes int64
+	// Done is true on the last progress report of the file.
+	Done bool
+}
+
+// DownloadProgressFunc is a callback used to receive progress reports during
+// downloads.
+type DownloadProgressFunc func(DownloadProgress)
+
+// ArtifactDownloadOptions holds optional settings used when downloading
+// artifacts.
+type ArtifactDownloadOptions struct {
+	// Progress, if set, is called every time bytes has been received, and a
+	// final time when the file has been fully downloaded.
+	Progress DownloadProgressFunc
+	// Resume continues a previously interrupted download from the partially
+	// downloaded file, if any, using an HTTP Range request. If the server
+	// does not support range requests, the download starts over. If the
+	// partial file already holds the whole artifact, nothing more is
+	// downloaded, and the FileName and ContentType fields of the result are
+	// left empty.
+	Resume bool
+	// MaxRetries is the number of times the download is resumed after the
+	// connection fails midway. Zero means no retries.
+	MaxRetries int
+	// SHA256 enables computing the SHA-256 checksum of the file while it is
+	// being downloaded.
+	SHA256 bool
+}
+
+// ArtifactDownload holds metadata about a downloaded artifact.
+type ArtifactDownload struct {
+	// Path is the file path the artifact was written to.
+	Path string
+	// FileName is the file name as given by the Content-Disposition response
+	// header, if any.
+	FileName string
+	// ContentType is the value of the Content-Type response header.
+	ContentType string
+	// ContentLength is the total size of the artifact, or -1 if unknown.
+	ContentLength int64
+	// BytesWritten is the size of the written file.
+	BytesWritten int64
+	// Resumed is true if any part of the file was downloaded using a range
+	// request.
+	Resumed bool
+	// SHA256 is the hex-encoded SHA-256 checksum of the file, if it was
+	// enabled via the ArtifactDownloadOptions.SHA256 field.
+	SHA256 string
+}
+
+// partialFileSuffix is appended to the destination path while downloading, so
+// that the destination is only ever written to atomically via a rename.
+const partialFileSuffix = ".part"
+
+type fileDownload struct {
+	client  *Client
+	path    string
+	dst     string
+	opts    ArtifactDownloadOptions
+	result  ArtifactDownload
+	hash    hash.Hash
+	file    *os.File
+	written int64
+}
+
+func (c *Client) downloadToFile(ctx context.Context, path, dst string, opts ArtifactDownloadOptions) (ArtifactDownload, error) {
+	d := fileDownload{
+		client: c,
+		path:   path,
+		dst:    dst,
+		opts:   opts,
+		result: ArtifactDownload{
+			Path:          dst,
+			ContentLength: -1,
+		},
+	}
+	if opts.SHA256 {
+		d.hash = sha256.New()
+	}
+	if err := d.openPartialFile(); err != nil {
+		return ArtifactDownload{}, err
+	}
+	if err := d.download(ctx); err != nil {
+		d.file.Close()
+		return ArtifactDownload{}, err
+	}
+	if err := d.file.Sync(); err != nil {
+		d.file.Close()
+		return ArtifactDownload{}, err
+	}
+	if err := d.file.Close(); err != nil {
+		return ArtifactDownload{}, err
+	}
+	if err := os.Rename(d.partialPath(), dst); err != nil {
+		return ArtifactDownload{}, err
+	}
+	d.result.BytesWritten = d.written
+	if d.hash != nil {
+		d.result.SHA256 = hex.EncodeToString(d.hash.Sum(nil))
+	}
+	return d.result, nil
+}
+
+func (d *fileDownload) partialPath() string {
+	return d.dst + partialFileSuffix
+}
+
+func (d *fileDownload) openPartialFile() error {
+	flag := os.O_RDWR | os.O_CREATE
+	if !d.opts.Resume {
+		flag |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(d.partialPath(), flag, 0644)
+	if err != nil {
+		return err
+	}
+	d.file = f
+	if !d.opts.Resume {
+		return nil
+	}
+	// Bring the hash and offset up to speed with the already downloaded part.
+	var w io.Writer = io.Discard
+	if d.hash != nil {
+		w = d.hash
+	}
+	n, err := io.Copy(w, f)
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("read partial file: %w", err)
+	}
+	d.written = n
+	return nil
+}
+
+func (d *fileDownload) restart() error {
+	if err := d.file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := d.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if d.hash != nil {
+		d.hash.Reset()
+	}
+	d.written = 0
+	return nil
+}
+
+func (d *fileDownload) download(ctx context.Context) error {
+	for attempt := 0; ; attempt++ {
+		supportsRange, err := d.downloadAttempt(ctx)
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if attempt >= d.opts.MaxRetries || !supportsRange {
+			return err
+		}
+		log.Debug().
+			WithError(err).
+			WithString("path", d.path).
+			WithInt64("offset", d.written).
+			Message("Download interrupted, resuming.")
+	}
+}
+
+func (d *fileDownload) downloadAttempt(ctx context.Context) (supportsRange bool, finalErr error) {
+	req, err := d.client.newRequest(ctx, http.MethodGet, d.path, nil, nil)
+	if err != nil {
+		return false, err
+	}
+	if d.written > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", d.written))
+	}
+	resp, err := d.client.doRequestResponse(req, http.StatusRequestedRangeNotSatisfiable)
+	if err != nil {
+		return false, err
+	}
+	defer closeAndSetError(resp.Body, &finalErr)
+
+	if resp.StatusCode == http.StatusRequestedRangeNotSatisfiable {
+		return d.rangeNotSatisfiable(ctx, resp)
+	}
+
+	supportsRange = resp.Header.Get("Accept-Ranges") == "bytes"
+	total := int64(-1)
+	if resp.StatusCode == http.StatusPartialContent {
+		start, size, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != d.written {
+			return false, fmt.Errorf("unexpected Content-Range: %q", resp.Header.Get("Content-Range"))
+		}
+		supportsRange = true
+		total = size
+		d.result.Resumed = true
+	} else {
+		if d.written > 0 {
+			log.Debug().
+				WithString("path", d.path).
+				Message("Server does not support range requests, restarting download.")
+		}
+		if err := d.restart(); err != nil {
+			return false, err
+		}
+		if resp.ContentLength >= 0 {
+			total = resp.ContentLength
+		}
+	}
+	d.readResponseMeta(resp, total)
+
+	var w io.Writer = d.file
+	if d.hash != nil {
+		w = io.MultiWriter(d.file, d.hash)
+	}
+	var r io.Reader = contextReader{ctx, resp.Body}
+	progressFileName := d.result.FileName
+	if progressFileName == "" {
+		progressFileName = filepath.Base(d.dst)
+	}
+	progress := DownloadProgress{
+		FileName:   progressFileName,
+		TotalBytes: total,
+	}
+	buf := make([]byte, 32*1024)
+	for {
+		n, readErr := r.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return supportsRange, err
+			}
+			d.written += int64(n)
+			if d.opts.Progress != nil {
+				progress.BytesReceived = d.written
+				d.opts.Progress(progress)
+			}
+		}
+		if errors.Is(readErr, io.EOF) {
+			break
+		}
+		if readErr != nil {
+			return supportsRange, readErr
+		}
+	}
+	if total >= 0 && d.written != total {
+		return supportsRange, fmt.Errorf("%w: got %d of %d bytes",
+			io.ErrUnexpectedEOF, d.written, total)
+	}
+	if d.opts.Progress != nil {
+		progress.BytesReceived = d.written
+		progress.Done = true
+		d.opts.Progress(progress)
+	}
+	return supportsRange, nil
+}
+
+// rangeNotSatisfiable handles a 416 response to a range request, which is
+// sent when the partial file is already as large as the artifact. The
+// download is complete if the sizes match, and is otherwise restarted.
+func (d *fileDownload) rangeNotSatisfiable(ctx context.Context, resp *http.Response) (bool, error) {
+	total, ok := parseUnsatisfiedContentRange(resp.Header.Get("Content-Range"))
+	if d.written == 0 || !ok || total != d.written {
+		log.Debug().
+			WithString("path", d.path).
+			WithInt64("offset", d.written).
+			Message("Partial file does not match artifact, restarting download.")
+		if err := d.restart(); err != nil {
+			return false, err
+		}
+		return d.downloadAttempt(ctx)
+	}
+	d.result.ContentLength = total
+	d.result.Resumed = true
+	if d.opts.Progress != nil {
+		d.opts.Progress(DownloadProgress{
+			FileName:      filepath.Base(d.dst),
+			BytesReceived: d.written,
+			TotalBytes:    total,
+			Done:          true,
+		})
+	}
+	return true, nil
+}
+
+func (d *fileDownload) readResponseMeta(resp *http.Response, total int64) {
+	d.result.ContentType = resp.Header.Get("Content-Type")
+	d.result.ContentLength = total
+	if fileName, ok := parseContentDispositionFileName(resp.Header.Get("Content-Disposition")); ok {
+		d.result.FileName = fileName
+	}
+}
+
+func parseContentDispositionFileName(value string) (string, bool) {
+	if value == "" {
+		return "", false
+	}
+	_, params, err := mime.ParseMediaType(value)
+	if err != nil {
+		return "", false
+	}
+	fileName, ok := params["filename"]
+	return fileName, ok && fileName != ""
+}
+
+// parseUnsatisfiedContentRange parses the Content-Range header of a 416
+// response, such as "bytes */200", and returns the total size.
+func parseUnsatisfiedContentRange(value string) (total int64, ok bool) {
+	unit, rest, ok := cutString(value, ' ')
+	if !ok || unit != "bytes" {
+		return 0, false
+	}
+	rangeStr, totalStr, ok := cutString(rest, '/')
+	if !ok || strings.TrimSpace(rangeStr) != "*" {
+		return 0, false
+	}
+	total, err := strconv.ParseInt(strings.TrimSpace(totalStr), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return total, true
+}
+
+// parseContentRange parses the Content-Range header, such as
+// "bytes 100-199/200", and returns the start offset and the total size, where
+// the total size is -1 if unknown.
+func parseContentRange(value string) (start, total int64, ok bool) {
+	unit, rest, ok := cutString(value, ' ')
+	if !ok || unit != "bytes" {
+		return 0, 0, false
+	}
+	rangeStr, totalStr, ok := cutString(rest, '/')
+	if !ok {
+		return 0, 0, false
+	}
+	startStr, _, ok := cutString(rangeStr, '-')
+	if !ok {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(strings.TrimSpace(startStr), 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	if totalStr == "*" {
+		return start, -1, true
+	}
+	total, err = strconv.ParseInt(strings.TrimSpace(totalStr), 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return start, total, true
+}
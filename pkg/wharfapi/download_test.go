@@ -0,0 +1,204 @@
+package wharfapi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testArtifactContent = "lorem ipsum dolor sit amet"
+
+func newTestDownloadServer(t *testing.T, failFirstAfter int) *httptest.Server {
+	failed := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Disposition", `attachment; filename="lorem.txt"`)
+		w.Header().Set("Content-Type", "text/plain")
+		if failFirstAfter > 0 && !failed {
+			failed = true
+			w.Header().Set("Accept-Ranges", "bytes")
+			w.Header().Set("Content-Length", "26")
+			w.Write([]byte(testArtifactContent[:failFirstAfter]))
+			w.(http.Flusher).Flush()
+			// Closing the connection early leads to an unexpected EOF.
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if assert.NoError(t, err) {
+				conn.Close()
+			}
+			return
+		}
+		http.ServeContent(w, r, "", time.Time{}, strings.NewReader(testArtifactContent))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func testSHA256(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestDownloadBuildArtifact(t *testing.T) {
+	srv := newTestDownloadServer(t, 0)
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+	dst := filepath.Join(t.TempDir(), "out.txt")
+
+	var last DownloadProgress
+	got, err := c.DownloadBuildArtifact(context.Background(), 1, 2, dst, ArtifactDownloadOptions{
+		SHA256:   true,
+		Progress: func(p DownloadProgress) { last = p },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ArtifactDownload{
+		Path:          dst,
+		FileName:      "lorem.txt",
+		ContentType:   "text/plain",
+		ContentLength: 26,
+		BytesWritten:  26,
+		SHA256:        testSHA256(testArtifactContent),
+	}, got)
+	assert.Equal(t, DownloadProgress{FileName: "lorem.txt", BytesReceived: 26, TotalBytes: 26, Done: true}, last)
+
+	b, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, testArtifactContent, string(b))
+	assert.NoFileExists(t, dst+partialFileSuffix)
+}
+
+func TestDownloadBuildArtifact_resumePartialFile(t *testing.T) {
+	srv := newTestDownloadServer(t, 0)
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+	dst := filepath.Join(t.TempDir(), "out.txt")
+	require.NoError(t, os.WriteFile(dst+partialFileSuffix, []byte(testArtifactContent[:10]), 0644))
+
+	got, err := c.DownloadBuildArtifact(context.Background(), 1, 2, dst, ArtifactDownloadOptions{
+		Resume: true,
+		SHA256: true,
+	})
+	require.NoError(t, err)
+	assert.True(t, got.Resumed, "resumed")
+	assert.Equal(t, int64(26), got.BytesWritten)
+	assert.Equal(t, testSHA256(testArtifactContent), got.SHA256)
+
+	b, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, testArtifactContent, string(b))
+}
+
+func TestDownloadBuildArtifact_resumeCompletePartialFile(t *testing.T) {
+	srv := newTestDownloadServer(t, 0)
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+	dst := filepath.Join(t.TempDir(), "out.txt")
+	require.NoError(t, os.WriteFile(dst+partialFileSuffix, []byte(testArtifactContent), 0644))
+
+	got, err := c.DownloadBuildArtifact(context.Background(), 1, 2, dst, ArtifactDownloadOptions{
+		Resume: true,
+		SHA256: true,
+	})
+	require.NoError(t, err)
+	assert.True(t, got.Resumed, "resumed")
+	assert.Equal(t, int64(26), got.ContentLength)
+	assert.Equal(t, int64(26), got.BytesWritten)
+	assert.Equal(t, testSHA256(testArtifactContent), got.SHA256)
+
+	b, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, testArtifactContent, string(b))
+}
+
+func TestDownloadBuildArtifact_resumeOversizedPartialFile(t *testing.T) {
+	srv := newTestDownloadServer(t, 0)
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+	dst := filepath.Join(t.TempDir(), "out.txt")
+	require.NoError(t, os.WriteFile(dst+partialFileSuffix, []byte(testArtifactContent+" extra"), 0644))
+
+	got, err := c.DownloadBuildArtifact(context.Background(), 1, 2, dst, ArtifactDownloadOptions{
+		Resume: true,
+		SHA256: true,
+	})
+	require.NoError(t, err)
+	assert.False(t, got.Resumed, "resumed")
+	assert.Equal(t, int64(26), got.BytesWritten)
+	assert.Equal(t, testSHA256(testArtifactContent), got.SHA256)
+
+	b, err := os.ReadFile(dst)
+	require.NoError(t, err)
+	assert.Equal(t, testArtifactContent, string(b))
+}
+
+func TestDownloadBuildArtifact_retryAfterInterruption(t *testing.T) {
+	srv := newTestDownloadServer(t, 5)
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+	dst := filepath.Join(t.TempDir(), "out.txt")
+
+	got, err := c.DownloadBuildArtifact(context.Background(), 1, 2, dst, ArtifactDownloadOptions{
+		MaxRetries: 1,
+		SHA256:     true,
+	})
+	require.NoError(t, err)
+	assert.True(t, got.Resumed, "resumed")
+	assert.Equal(t, testSHA256(testArtifactContent), got.SHA256)
+}
+
+func TestDownloadBuildArtifact_interruptedWithoutRetries(t *testing.T) {
+	srv := newTestDownloadServer(t, 5)
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+	dst := filepath.Join(t.TempDir(), "out.txt")
+
+	_, err := c.DownloadBuildArtifact(context.Background(), 1, 2, dst, ArtifactDownloadOptions{})
+	require.Error(t, err)
+	assert.NoFileExists(t, dst)
+	assert.FileExists(t, dst+partialFileSuffix)
+}
+
+func TestParseContentRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		wantStart int64
+		wantTotal int64
+		wantOK    bool
+	}{
+		{
+			name:      "known total",
+			value:     "bytes 100-199/200",
+			wantStart: 100,
+			wantTotal: 200,
+			wantOK:    true,
+		},
+		{
+			name:      "unknown total",
+			value:     "bytes 5-9/*",
+			wantStart: 5,
+			wantTotal: -1,
+			wantOK:    true,
+		},
+		{
+			name:  "wrong unit",
+			value: "lines 5-9/10",
+		},
+		{
+			name:  "empty",
+			value: "",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			start, total, ok := parseContentRange(tc.value)
+			assert.Equal(t, tc.wantOK, ok)
+			if tc.wantOK {
+				assert.Equal(t, tc.wantStart, start)
+				assert.Equal(t, tc.wantTotal, total)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package wharfapi
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const defaultHTTPDumpMaxBodySize = 4096
+
+// DefaultHTTPDumpHeaders are the headers logged by the HTTP dump if
+// HTTPDumpOptions.Headers is nil.
+var DefaultHTTPDumpHeaders = []string{
+	"Accept",
+	"Authorization",
+	"Content-Length",
+	"Content-Range",
+	"Content-Type",
+	"Location",
+	"Range",
+	"Retry-After",
+	"WWW-Authenticate",
+}
+
+// requestIDHeaders are the headers checked, in order, for the server's
+// request ID.
+var requestIDHeaders = []string{
+	"X-Request-Id",
+	"X-Correlation-Id",
+	"Request-Id",
+}
+
+// HTTPDumpOptions enables and configures the logging of HTTP request and
+// response dumps, for debugging. The dumps are logged at debug level.
+//
+// All logged values are redacted using the Client.Redactor.
+type HTTPDumpOptions struct {
+	// Headers are the names of the request and response headers to log.
+	// Uses DefaultHTTPDumpHeaders if nil.
+	Headers []string
+	// MaxBodySize is the maximum number of bytes of each request and response
+	// body to log, after which it is truncated. Defaults to 4096 if zero.
+	// Bodies are not logged if negative.
+	MaxBodySize int
+}
+
+func (o HTTPDumpOptions) headers() []string {
+	if o.Headers == nil {
+		return DefaultHTTPDumpHeaders
+	}
+	return o.Headers
+}
+
+func (o HTTPDumpOptions) maxBodySize() int {
+	if o.MaxBodySize == 0 {
+		return defaultHTTPDumpMaxBodySize
+	}
+	return o.MaxBodySize
+}
+
+type httpDump struct {
+	opts     HTTPDumpOptions
+	redactor *Redactor
+	req      *http.Request
+	reqBody  *bodyCapture
+	start    time.Time
+}
+
+// newHTTPDump starts dumping the request, and wraps its body so the sent
+// body can be logged once the response is received.
+func (c *Client) newHTTPDump(req *http.Request) *httpDump {
+	d := &httpDump{
+		opts:     *c.HTTPDump,
+		redactor: c.redactor(),
+		req:      req,
+		start:    time.Now(),
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		d.reqBody = newBodyCapture(req.Body, req.Header.Get("Content-Type"), d.opts.maxBodySize())
+		req.Body = d.reqBody
+	}
+	return d
+}
+
+// logResponse logs the request and response metadata, and wraps the response
+// body so that it is logged when closed.
+func (d *httpDump) logResponse(resp *http.Response, err error) {
+	latency := time.Since(d.start)
+	ev := log.Debug().
+		WithString("method", d.req.Method).
+		WithString("url", d.redactor.URL(d.req.URL.String())).
+		WithDuration("latency", latency).
+		WithStringer("requestHeaders", dumpHeaders{d.req.Header, d.opts.headers(), d.redactor})
+	if d.reqBody != nil {
+		ev = ev.WithStringer("requestBody", dumpBody{d.reqBody, d.redactor})
+	}
+	if err != nil {
+		ev.WithError(err).Message("HTTP request failed.")
+		return
+	}
+	ev = ev.WithInt("status", resp.StatusCode).
+		WithStringer("responseHeaders", dumpHeaders{resp.Header, d.opts.headers(), d.redactor})
+	requestID := responseRequestID(resp)
+	if requestID != "" {
+		ev = ev.WithString("requestId", requestID)
+	}
+	ev.Message("HTTP request.")
+
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return
+	}
+	body := newBodyCapture(resp.Body, resp.Header.Get("Content-Type"), d.opts.maxBodySize())
+	resp.Body = &loggingBody{
+		bodyCapture: body,
+		onClose: func() {
+			ev := log.Debug().
+				WithString("method", d.req.Method).
+				WithString("url", d.redactor.URL(d.req.URL.String())).
+				WithInt("status", resp.StatusCode)
+			if requestID != "" {
+				ev = ev.WithString("requestId", requestID)
+			}
+			ev.WithStringer("responseBody", dumpBody{body, d.redactor}).
+				Message("HTTP response body.")
+		},
+	}
+}
+
+func responseRequestID(resp *http.Response) string {
+	for _, name := range requestIDHeaders {
+		if id := resp.Header.Get(name); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// bodyCapture passes through a body while keeping its first bytes, and
+// counting the total size.
+//
+// The request body is read by the transport on another goroutine, which may
+// still be reading when the response is logged, so the captured bytes are
+// guarded by a mutex.
+type bodyCapture struct {
+	io.ReadCloser
+	contentType string
+	max         int
+
+	mu       sync.Mutex
+	captured bytes.Buffer
+	total    int64
+}
+
+func newBodyCapture(body io.ReadCloser, contentType string, max int) *bodyCapture {
+	return &bodyCapture{ReadCloser: body, contentType: contentType, max: max}
+}
+
+func (b *bodyCapture) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.total += int64(n)
+	if remaining := b.max - b.captured.Len(); remaining > 0 && n > 0 && b.shouldCapture() {
+		if n < remaining {
+			remaining = n
+		}
+		b.captured.Write(p[:remaining])
+	}
+	return n, err
+}
+
+// snapshot returns a copy of the bytes captured so far, and the total number
+// of bytes read.
+func (b *bodyCapture) snapshot() ([]byte, int64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]byte(nil), b.captured.Bytes()...), b.total
+}
+
+// shouldCapture returns true for textual content, and for content without a
+// content type, such as the JSON request bodies, that may be textual.
+func (b *bodyCapture) shouldCapture() bool {
+	return b.contentType == "" || isDumpableContentType(b.contentType)
+}
+
+// loggingBody calls onClose once when closed.
+type loggingBody struct {
+	*bodyCapture
+	onClose func()
+	closed  bool
+}
+
+func (b *loggingBody) Close() error {
+	err := b.bodyCapture.Close()
+	if !b.closed {
+		b.closed = true
+		b.onClose()
+	}
+	return err
+}
+
+// isDumpableContentType returns true for textual content, so that
+// multipart uploads and binary artifacts are never logged.
+func isDumpableContentType(contentType string) bool {
+	if contentType == "" {
+		return false
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return strings.HasPrefix(mediaType, "text/") ||
+		mediaType == "application/json" ||
+		strings.HasSuffix(mediaType, "+json") ||
+		mediaType == "application/x-www-form-urlencoded"
+}
+
+// dumpBody formats a captured body, redacted and truncated. It implements
+// fmt.Stringer, so it can be passed to logger.Event.WithStringer to only
+// format when logged.
+type dumpBody struct {
+	body     *bodyCapture
+	redactor *Redactor
+}
+
+func (d dumpBody) String() string {
+	captured, total := d.body.snapshot()
+	if d.body.max < 0 {
+		return fmt.Sprintf("<%d bytes>", total)
+	}
+	if !d.body.shouldCapture() {
+		return fmt.Sprintf("<skipped %d bytes of %q>", total, d.body.contentType)
+	}
+	if !utf8.Valid(captured) {
+		return fmt.Sprintf("<skipped %d bytes of binary content>", total)
+	}
+	var s string
+	mediaType, _, _ := mime.ParseMediaType(d.body.contentType)
+	if mediaType == "application/x-www-form-urlencoded" {
+		s = d.redactor.Query(string(captured))
+	} else {
+		s = string(d.redactor.JSON(captured))
+	}
+	if total > int64(len(captured)) {
+		s += fmt.Sprintf("... (truncated, %d bytes total)", total)
+	}
+	return s
+}
+
+// dumpHeaders formats the selected headers, redacted. It implements
+// fmt.Stringer, so it can be passed to logger.Event.WithStringer to only
+// format when logged.
+type dumpHeaders struct {
+	header   http.Header
+	names    []string
+	redactor *Redactor
+}
+
+func (d dumpHeaders) String() string {
+	selected := http.Header{}
+	for _, name := range d.names {
+		if values := d.header.Values(name); len(values) > 0 {
+			selected[http.CanonicalHeaderKey(name)] = values
+		}
+	}
+	return fmt.Sprint(d.redactor.Header(selected))
+}
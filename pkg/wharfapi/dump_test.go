@@ -0,0 +1,81 @@
+package wharfapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/request"
+	"github.com/iver-wharf/wharf-core/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPDump(t *testing.T) {
+	mock := logger.NewMock()
+	logger.AddOutput(logger.LevelDebug, mock)
+	defer logger.ClearOutputs()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "req-123")
+		fmt.Fprintf(w, `{"tokenId":1,"token":"ghp_secret-value","userName":%q}`, strings.Repeat("x", 100))
+	}))
+	defer srv.Close()
+	c := Client{
+		APIURL:                 srv.URL,
+		AuthHeader:             "Bearer my-auth-token",
+		DisableOutdatedLogging: true,
+		HTTPDump:               &HTTPDumpOptions{MaxBodySize: 40},
+	}
+
+	_, err := c.CreateToken(request.Token{Token: "ghp_new-secret", UserName: "bot"})
+	require.NoError(t, err)
+
+	reqLog := findMockLog(t, mock, "HTTP request.")
+	assert.Equal(t, "POST", reqLog.Fields["method"])
+	assert.Equal(t, 200, reqLog.Fields["status"])
+	assert.Equal(t, "req-123", reqLog.Fields["requestId"])
+	assert.Contains(t, reqLog.Fields["requestHeaders"], "Bearer *REDACTED*")
+	assert.Contains(t, reqLog.Fields["requestBody"], `"token":"*REDACTED*"`)
+	assert.Contains(t, reqLog.Fields, "latency")
+
+	respLog := findMockLog(t, mock, "HTTP response body.")
+	assert.Equal(t, "req-123", respLog.Fields["requestId"])
+	body := respLog.Fields["responseBody"].(string)
+	assert.Contains(t, body, `"token":"*REDACTED*"`)
+	assert.Contains(t, body, "... (truncated, ")
+
+	for _, l := range mock.Logs {
+		for key, value := range l.Fields {
+			assert.NotContains(t, fmt.Sprint(value), "secret-value", "field %q in log %q", key, l.Message)
+			assert.NotContains(t, fmt.Sprint(value), "new-secret", "field %q in log %q", key, l.Message)
+			assert.NotContains(t, fmt.Sprint(value), "my-auth-token", "field %q in log %q", key, l.Message)
+		}
+	}
+}
+
+func TestHTTPDump_skipsBinaryBodies(t *testing.T) {
+	body := newBodyCapture(nopReadCloser{strings.NewReader("PK\x03\x04")}, "application/zip", 100)
+	_, err := body.Read(make([]byte, 10))
+	require.NoError(t, err)
+	assert.Equal(t, `<skipped 4 bytes of "application/zip">`, dumpBody{body, &defaultRedactor}.String())
+}
+
+func findMockLog(t *testing.T, mock *logger.Mock, message string) logger.MockLog {
+	for _, l := range mock.Logs {
+		if l.Message == message {
+			return l
+		}
+	}
+	t.Fatalf("no log with message %q, got: %q", message, mock.LogMessages)
+	return logger.MockLog{}
+}
+
+type nopReadCloser struct {
+	*strings.Reader
+}
+
+func (nopReadCloser) Close() error { return nil }
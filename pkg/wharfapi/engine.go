@@ -1,10 +1,39 @@
 package wharfapi
 
-import "github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+import (
+	"errors"
+	"fmt"
+	"strings"
 
-// GetEngineList filters builds based on the parameters by invoking the HTTP
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+)
+
+// ErrNoDefaultEngine is returned when resolving an empty engine ID while the
+// wharf-api has no default execution engine configured.
+var ErrNoDefaultEngine = errors.New("no default engine")
+
+// UnknownEngineError is returned when an engine ID or name was requested that
+// does not exist in the wharf-api's list of execution engines.
+type UnknownEngineError struct {
+	Engine string
+	Valid  []response.Engine
+}
+
+func (e *UnknownEngineError) Error() string {
+	ids := make([]string, 0, len(e.Valid))
+	for _, engine := range e.Valid {
+		ids = append(ids, fmt.Sprintf("%q", engine.ID))
+	}
+	if len(ids) == 0 {
+		return fmt.Sprintf("unknown engine %q: no engines are configured", e.Engine)
+	}
+	return fmt.Sprintf("unknown engine %q: valid engines are %s",
+		e.Engine, strings.Join(ids, ", "))
+}
+
+// GetEngineList gets the list of execution engines by invoking the HTTP
 // request:
-//  GET /api/build
+//  GET /api/engine
 //
 // Added in wharf-api v5.1.0.
 func (c *Client) GetEngineList() (response.EngineList, error) {
@@ -15,3 +44,70 @@ func (c *Client) GetEngineList() (response.EngineList, error) {
 	err := c.getUnmarshal("/api/engine", nil, &list)
 	return list, err
 }
+
+// SetCachedEngineList will override the list of execution engines that the
+// wharf-api-client-go uses when resolving engines, such as when the
+// Client.ValidateEngine flag is enabled.
+func (c *Client) SetCachedEngineList(list response.EngineList) {
+	c.cachedEngineList = &list
+}
+
+// ResetCachedEngineList will reset the list of execution engines that the
+// wharf-api client thinks the remote API has, and will then fetch a fresh list
+// on the next engine resolution.
+func (c *Client) ResetCachedEngineList() {
+	c.cachedEngineList = nil
+}
+
+func (c *Client) getCachedOrFetchedEngineList() (response.EngineList, error) {
+	if c.cachedEngineList != nil {
+		return *c.cachedEngineList, nil
+	}
+	list, err := c.GetEngineList()
+	if err != nil {
+		return response.EngineList{}, err
+	}
+	c.cachedEngineList = &list
+	return list, nil
+}
+
+// ResolveEngine looks up an execution engine from the cached engine list,
+// fetching the list from the wharf-api first if it has not been cached yet.
+//
+// The engine is matched on its ID first, and then on its name while ignoring
+// casing. An empty string resolves to the default engine, which is the engine
+// that wharf-api will use when no engine is specified when starting a build.
+//
+// An *UnknownEngineError is returned if no engine matched, and
+// ErrNoDefaultEngine is returned if an empty string was given but the
+// wharf-api has no default engine.
+func (c *Client) ResolveEngine(idOrName string) (response.Engine, error) {
+	list, err := c.getCachedOrFetchedEngineList()
+	if err != nil {
+		return response.Engine{}, fmt.Errorf("get engine list: %w", err)
+	}
+	return resolveEngine(list, idOrName)
+}
+
+func resolveEngine(list response.EngineList, idOrName string) (response.Engine, error) {
+	if idOrName == "" {
+		if list.DefaultEngine == nil {
+			return response.Engine{}, ErrNoDefaultEngine
+		}
+		return *list.DefaultEngine, nil
+	}
+	for _, engine := range list.List {
+		if engine.ID == idOrName {
+			return engine, nil
+		}
+	}
+	for _, engine := range list.List {
+		if strings.EqualFold(engine.Name, idOrName) {
+			return engine, nil
+		}
+	}
+	return response.Engine{}, &UnknownEngineError{
+		Engine: idOrName,
+		Valid:  list.List,
+	}
+}
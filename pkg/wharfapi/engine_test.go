@@ -0,0 +1,79 @@
+package wharfapi
+
+import (
+	"testing"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveEngine_ok(t *testing.T) {
+	primary := response.Engine{ID: "primary", Name: "Primary"}
+	jenkins := response.Engine{ID: "jenkins", Name: "Jenkins"}
+	secondary := response.Engine{ID: "Jenkins-2", Name: "primary"}
+	list := response.EngineList{
+		DefaultEngine: &primary,
+		List:          []response.Engine{primary, jenkins, secondary},
+	}
+
+	tests := []struct {
+		name     string
+		idOrName string
+		want     response.Engine
+	}{
+		{
+			name:     "empty resolves to default",
+			idOrName: "",
+			want:     primary,
+		},
+		{
+			name:     "by ID",
+			idOrName: "jenkins",
+			want:     jenkins,
+		},
+		{
+			name:     "by name ignoring case",
+			idOrName: "JENKINS",
+			want:     jenkins,
+		},
+		{
+			name:     "ID takes precedence over name",
+			idOrName: "primary",
+			want:     primary,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := resolveEngine(list, tc.idOrName)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestResolveEngine_unknown(t *testing.T) {
+	list := response.EngineList{
+		List: []response.Engine{{ID: "primary"}, {ID: "jenkins"}},
+	}
+	_, err := resolveEngine(list, "foo")
+	var unknownErr *UnknownEngineError
+	require.ErrorAs(t, err, &unknownErr)
+	assert.Equal(t, "foo", unknownErr.Engine)
+	assert.EqualError(t, err, `unknown engine "foo": valid engines are "primary", "jenkins"`)
+}
+
+func TestResolveEngine_noDefault(t *testing.T) {
+	_, err := resolveEngine(response.EngineList{}, "")
+	assert.ErrorIs(t, err, ErrNoDefaultEngine)
+}
+
+func TestClientResolveEngine_usesCache(t *testing.T) {
+	c := Client{}
+	c.SetCachedEngineList(response.EngineList{
+		List: []response.Engine{{ID: "primary", Name: "Primary"}},
+	})
+	got, err := c.ResolveEngine("Primary")
+	require.NoError(t, err)
+	assert.Equal(t, "primary", got.ID)
+}
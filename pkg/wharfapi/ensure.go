@@ -0,0 +1,224 @@
+package wharfapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/request"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+)
+
+// EnsureOutcome is an enum of what an Ensure method had to do to make the
+// record match the requested state.
+type EnsureOutcome string
+
+const (
+	// EnsureUnchanged means the record already matched.
+	EnsureUnchanged EnsureOutcome = "unchanged"
+	// EnsureCreated means the record did not exist and was created.
+	EnsureCreated EnsureOutcome = "created"
+	// EnsureUpdated means the record existed but was updated.
+	EnsureUpdated EnsureOutcome = "updated"
+)
+
+// Changed returns true if the record was created or updated.
+func (o EnsureOutcome) Changed() bool {
+	return o == EnsureCreated || o == EnsureUpdated
+}
+
+// EnsureToken makes sure a token with the given user name exists and has the
+// given token value, by looking it up using FindToken and then creating or
+// updating it as needed, by invoking the HTTP requests:
+//  GET /api/token?userName={userName}
+//  POST /api/token
+//  PUT /api/token/{tokenId}
+//
+// The ProviderID field is only used when creating the token.
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) EnsureToken(ctx context.Context, token request.Token) (response.Token, EnsureOutcome, error) {
+	current, err := c.FindToken(ctx, token.UserName)
+	if isNotFound(err) {
+		var created response.Token
+		err := c.postJSONUnmarshalContext(ctx, "/api/token", nil, token, &created)
+		if err != nil {
+			return response.Token{}, "", fmt.Errorf("ensure token: create: %w", err)
+		}
+		return created, EnsureCreated, nil
+	}
+	if err != nil {
+		return response.Token{}, "", fmt.Errorf("ensure token: %w", err)
+	}
+	orig := request.TokenUpdate{
+		Token:    current.Token,
+		UserName: current.UserName,
+	}
+	update := request.TokenUpdate{
+		Token:    token.Token,
+		UserName: token.UserName,
+	}
+	if update == orig {
+		return current, EnsureUnchanged, nil
+	}
+	var updated response.Token
+	path := fmt.Sprintf("/api/token/%d", current.TokenID)
+	if err := c.putJSONUnmarshalContext(ctx, path, nil, update, &updated); err != nil {
+		return response.Token{}, "", fmt.Errorf("ensure token: update: %w", err)
+	}
+	return updated, EnsureUpdated, nil
+}
+
+// EnsureProvider makes sure a provider with the given name and URL exists and
+// uses the given token, by looking it up using FindProvider and then creating
+// or updating it as needed, by invoking the HTTP requests:
+//  GET /api/provider?name={name}
+//  POST /api/provider
+//  PUT /api/provider/{providerId}
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) EnsureProvider(ctx context.Context, provider request.Provider) (response.Provider, EnsureOutcome, error) {
+	current, err := c.FindProvider(ctx, string(provider.Name), provider.URL)
+	if isNotFound(err) {
+		var created response.Provider
+		err := c.postJSONUnmarshalContext(ctx, "/api/provider", nil, provider, &created)
+		if err != nil {
+			return response.Provider{}, "", fmt.Errorf("ensure provider: create: %w", err)
+		}
+		return created, EnsureCreated, nil
+	}
+	if err != nil {
+		return response.Provider{}, "", fmt.Errorf("ensure provider: %w", err)
+	}
+	if current.TokenID == provider.TokenID {
+		return current, EnsureUnchanged, nil
+	}
+	// Keeps the stored URL, as it only differs in ways that FindProvider
+	// considers equal.
+	update := request.ProviderUpdate{
+		Name:    provider.Name,
+		URL:     current.URL,
+		TokenID: provider.TokenID,
+	}
+	var updated response.Provider
+	path := fmt.Sprintf("/api/provider/%d", current.ProviderID)
+	if err := c.putJSONUnmarshalContext(ctx, path, nil, update, &updated); err != nil {
+		return response.Provider{}, "", fmt.Errorf("ensure provider: update: %w", err)
+	}
+	return updated, EnsureUpdated, nil
+}
+
+// EnsureProject makes sure a project with the given group name and name exists
+// and has the given fields, by looking it up using FindProject and then
+// creating or updating it as needed, by invoking the HTTP requests:
+//  GET /api/project?groupName={groupName}&name={name}
+//  POST /api/project
+//  PUT /api/project/{projectId}
+//
+// The RemoteProjectID field is only used when creating the project.
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) EnsureProject(ctx context.Context, project request.Project) (response.Project, EnsureOutcome, error) {
+	current, err := c.FindProject(ctx, project.GroupName, project.Name)
+	if isNotFound(err) {
+		var created response.Project
+		err := c.postJSONUnmarshalContext(ctx, "/api/project", nil, project, &created)
+		if err != nil {
+			return response.Project{}, "", fmt.Errorf("ensure project: create: %w", err)
+		}
+		return created, EnsureCreated, nil
+	}
+	if err != nil {
+		return response.Project{}, "", fmt.Errorf("ensure project: %w", err)
+	}
+	orig := request.ProjectUpdate{
+		Name:            current.Name,
+		GroupName:       current.GroupName,
+		Description:     current.Description,
+		AvatarURL:       current.AvatarURL,
+		TokenID:         current.TokenID,
+		ProviderID:      current.ProviderID,
+		BuildDefinition: current.BuildDefinition,
+		GitURL:          current.GitURL,
+	}
+	update := request.ProjectUpdate{
+		Name:            project.Name,
+		GroupName:       project.GroupName,
+		Description:     project.Description,
+		AvatarURL:       project.AvatarURL,
+		TokenID:         project.TokenID,
+		ProviderID:      project.ProviderID,
+		BuildDefinition: project.BuildDefinition,
+		GitURL:          project.GitURL,
+	}
+	if update == orig {
+		return current, EnsureUnchanged, nil
+	}
+	var updated response.Project
+	path := fmt.Sprintf("/api/project/%d", current.ProjectID)
+	if err := c.putJSONUnmarshalContext(ctx, path, nil, update, &updated); err != nil {
+		return response.Project{}, "", fmt.Errorf("ensure project: update: %w", err)
+	}
+	return updated, EnsureUpdated, nil
+}
+
+// EnsureBranches makes sure a project has exactly the given branches and
+// default branch, by fetching its current branches and then resetting them
+// only if they differ, by invoking the HTTP requests:
+//  GET /api/project/{projectId}/branch
+//  PUT /api/project/{projectId}/branch
+//
+// The order of the branches does not matter.
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) EnsureBranches(ctx context.Context, projectID uint, branches []request.Branch) ([]response.Branch, EnsureOutcome, error) {
+	if err := c.validateEndpointVersion(5, 0, 0); err != nil {
+		return nil, "", err
+	}
+	path := fmt.Sprintf("/api/project/%d/branch", projectID)
+	var current []response.Branch
+	if err := c.getUnmarshalContext(ctx, path, nil, &current); err != nil {
+		return nil, "", fmt.Errorf("ensure branches: %w", err)
+	}
+	currentRequest := make([]request.Branch, len(current))
+	for i, b := range current {
+		currentRequest[i] = request.Branch{Name: b.Name, Default: b.Default}
+	}
+	if isSameBranches(currentRequest, branches) {
+		return current, EnsureUnchanged, nil
+	}
+	updated, err := c.updateProjectBranchList(ctx, projectID, branches)
+	if err != nil {
+		return nil, "", fmt.Errorf("ensure branches: update: %w", err)
+	}
+	return updated, EnsureUpdated, nil
+}
+
+func isSameBranches(a, b []request.Branch) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	a = sortedBranches(a)
+	b = sortedBranches(b)
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedBranches(branches []request.Branch) []request.Branch {
+	sorted := make([]request.Branch, len(branches))
+	copy(sorted, branches)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	return sorted
+}
+
+func isNotFound(err error) bool {
+	var notFound *NotFoundError
+	return errors.As(err, &notFound)
+}
@@ -0,0 +1,93 @@
+package wharfapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnsureProject(t *testing.T) {
+	var existing bool
+	var writes []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/project":
+			if !existing {
+				fmt.Fprint(w, `{"totalCount":0,"list":[]}`)
+				return
+			}
+			fmt.Fprint(w, `{"totalCount":1,"list":[{"projectId":7,"groupName":"team","name":"api","description":"old"}]}`)
+		case r.Method == http.MethodPost && r.URL.Path == "/api/project":
+			writes = append(writes, "POST")
+			fmt.Fprint(w, `{"projectId":7,"groupName":"team","name":"api","description":"old"}`)
+		case r.Method == http.MethodPut && r.URL.Path == "/api/project/7":
+			writes = append(writes, "PUT")
+			var update request.ProjectUpdate
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&update))
+			fmt.Fprintf(w, `{"projectId":7,"description":%q}`, update.Description)
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+		}
+	}))
+	defer srv.Close()
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+	ctx := context.Background()
+
+	project := request.Project{GroupName: "team", Name: "api", Description: "old"}
+	_, outcome, err := c.EnsureProject(ctx, project)
+	require.NoError(t, err)
+	assert.Equal(t, EnsureCreated, outcome)
+
+	existing = true
+	_, outcome, err = c.EnsureProject(ctx, project)
+	require.NoError(t, err)
+	assert.Equal(t, EnsureUnchanged, outcome)
+	assert.False(t, outcome.Changed())
+
+	project.Description = "new"
+	updated, outcome, err := c.EnsureProject(ctx, project)
+	require.NoError(t, err)
+	assert.Equal(t, EnsureUpdated, outcome)
+	assert.Equal(t, "new", updated.Description)
+	assert.Equal(t, []string{"POST", "PUT"}, writes)
+}
+
+func TestEnsureBranches(t *testing.T) {
+	var puts int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/project/7/branch", r.URL.Path)
+		if r.Method == http.MethodPut {
+			puts++
+			fmt.Fprint(w, `{"branches":[{"name":"main","default":true},{"name":"dev"},{"name":"feature"}]}`)
+			return
+		}
+		fmt.Fprint(w, `[{"name":"main","default":true},{"name":"dev"}]`)
+	}))
+	defer srv.Close()
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+	ctx := context.Background()
+
+	_, outcome, err := c.EnsureBranches(ctx, 7, []request.Branch{
+		{Name: "dev"},
+		{Name: "main", Default: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, EnsureUnchanged, outcome)
+
+	branches, outcome, err := c.EnsureBranches(ctx, 7, []request.Branch{
+		{Name: "main", Default: true},
+		{Name: "dev"},
+		{Name: "feature"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, EnsureUpdated, outcome)
+	assert.Len(t, branches, 3)
+	assert.Equal(t, 1, puts)
+}
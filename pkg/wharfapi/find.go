@@ -0,0 +1,211 @@
+package wharfapi
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/google/go-querystring/query"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+)
+
+// NotFoundError is returned by the Find methods when no record matches.
+type NotFoundError struct {
+	// Kind is the type of record, such as "project".
+	Kind string
+	// Query describes what was searched for.
+	Query string
+}
+
+// Error implements the error interface.
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s not found: %s", e.Kind, e.Query)
+}
+
+// AmbiguousMatchError is returned by the Find methods when multiple records
+// match.
+type AmbiguousMatchError struct {
+	// Kind is the type of record, such as "project".
+	Kind string
+	// Query describes what was searched for.
+	Query string
+	// IDs are the IDs of all matching records.
+	IDs []uint
+}
+
+// Error implements the error interface.
+func (e *AmbiguousMatchError) Error() string {
+	ids := make([]string, len(e.IDs))
+	for i, id := range e.IDs {
+		ids[i] = fmt.Sprint(id)
+	}
+	return fmt.Sprintf("%s is ambiguous: %s: matches IDs %s", e.Kind, e.Query, strings.Join(ids, ", "))
+}
+
+// FindProject finds a project by its exact group name and name, by invoking
+// the HTTP request:
+//  GET /api/project?groupName={groupName}&name={name}
+//
+// Returns a *NotFoundError if no project matches, and an *AmbiguousMatchError
+// if multiple projects match.
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) FindProject(ctx context.Context, groupName, name string) (response.Project, error) {
+	projects, err := c.listProjects(ctx, ProjectSearch{GroupName: &groupName, Name: &name})
+	if err != nil {
+		return response.Project{}, err
+	}
+	var matches []response.Project
+	var ids []uint
+	for _, p := range projects {
+		if p.GroupName == groupName && p.Name == name {
+			matches = append(matches, p)
+			ids = append(ids, p.ProjectID)
+		}
+	}
+	q := fmt.Sprintf("group name %q and name %q", groupName, name)
+	if err := checkSingleMatch("project", q, ids); err != nil {
+		return response.Project{}, err
+	}
+	return matches[0], nil
+}
+
+// FindProjectByGitURL finds a project by its Git URL, by invoking the HTTP
+// request:
+//  GET /api/project?gitUrlMatch={repoName}
+//
+// The Git URLs are normalized using NormalizeGitURL before they are compared,
+// so an SSH URL matches the HTTPS URL of the same repository.
+//
+// Returns a *NotFoundError if no project matches, and an *AmbiguousMatchError
+// if multiple projects match.
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) FindProjectByGitURL(ctx context.Context, gitURL string) (response.Project, error) {
+	normalized := NormalizeGitURL(gitURL)
+	// The repository name is part of the URL regardless of its form, so
+	// use it to narrow down the search before comparing the full URLs.
+	repoName := path.Base(normalized)
+	projects, err := c.listProjects(ctx, ProjectSearch{GitURLMatch: &repoName})
+	if err != nil {
+		return response.Project{}, err
+	}
+	var matches []response.Project
+	var ids []uint
+	for _, p := range projects {
+		if NormalizeGitURL(p.GitURL) == normalized {
+			matches = append(matches, p)
+			ids = append(ids, p.ProjectID)
+		}
+	}
+	if err := checkSingleMatch("project", fmt.Sprintf("Git URL %q", gitURL), ids); err != nil {
+		return response.Project{}, err
+	}
+	return matches[0], nil
+}
+
+// FindProvider finds a provider by its exact name, such as "github", and URL,
+// by invoking the HTTP request:
+//  GET /api/provider?name={name}
+//
+// The URLs are compared case-insensitively and regardless of trailing
+// slashes.
+//
+// Returns a *NotFoundError if no provider matches, and an
+// *AmbiguousMatchError if multiple providers match.
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) FindProvider(ctx context.Context, name, providerURL string) (response.Provider, error) {
+	providers, err := c.listProviders(ctx, ProviderSearch{Name: &name})
+	if err != nil {
+		return response.Provider{}, err
+	}
+	var matches []response.Provider
+	var ids []uint
+	for _, p := range providers {
+		if string(p.Name) == name && normalizeProviderURL(p.URL) == normalizeProviderURL(providerURL) {
+			matches = append(matches, p)
+			ids = append(ids, p.ProviderID)
+		}
+	}
+	q := fmt.Sprintf("name %q and URL %q", name, providerURL)
+	if err := checkSingleMatch("provider", q, ids); err != nil {
+		return response.Provider{}, err
+	}
+	return matches[0], nil
+}
+
+// FindToken finds a token by its exact user name, by invoking the HTTP
+// request:
+//  GET /api/token?userName={userName}
+//
+// Returns a *NotFoundError if no token matches, and an *AmbiguousMatchError
+// if multiple tokens match.
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) FindToken(ctx context.Context, userName string) (response.Token, error) {
+	tokens, err := c.listTokens(ctx, TokenSearch{UserName: &userName})
+	if err != nil {
+		return response.Token{}, err
+	}
+	var matches []response.Token
+	var ids []uint
+	for _, t := range tokens {
+		if t.UserName == userName {
+			matches = append(matches, t)
+			ids = append(ids, t.TokenID)
+		}
+	}
+	if err := checkSingleMatch("token", fmt.Sprintf("user name %q", userName), ids); err != nil {
+		return response.Token{}, err
+	}
+	return matches[0], nil
+}
+
+func (c *Client) listContext(ctx context.Context, path string, params, page interface{}) error {
+	q, err := query.Values(params)
+	if err != nil {
+		return err
+	}
+	return c.getUnmarshalContext(ctx, path, q, page)
+}
+
+func checkSingleMatch(kind, query string, ids []uint) error {
+	switch len(ids) {
+	case 0:
+		return &NotFoundError{Kind: kind, Query: query}
+	case 1:
+		return nil
+	default:
+		return &AmbiguousMatchError{Kind: kind, Query: query, IDs: ids}
+	}
+}
+
+// NormalizeGitURL converts a Git URL to a comparable form of "host/path",
+// regardless of whether it is an SSH or HTTPS URL, so that different URLs of
+// the same repository are equal. The user info, port, trailing ".git" suffix,
+// and trailing slashes are removed, and the result is lowercased.
+//
+//  NormalizeGitURL("git@github.com:iver-wharf/wharf-api.git")       // => "github.com/iver-wharf/wharf-api"
+//  NormalizeGitURL("https://github.com/iver-wharf/wharf-api")       // => "github.com/iver-wharf/wharf-api"
+//  NormalizeGitURL("ssh://git@github.com:22/iver-wharf/wharf-api/") // => "github.com/iver-wharf/wharf-api"
+func NormalizeGitURL(gitURL string) string {
+	s := strings.TrimSpace(gitURL)
+	if strings.Contains(s, "://") {
+		if u, err := url.Parse(s); err == nil {
+			s = u.Hostname() + "/" + strings.TrimPrefix(u.Path, "/")
+		}
+	} else if at := strings.Index(s, "@"); at != -1 {
+		// SCP-like syntax, such as "git@github.com:org/repo.git".
+		s = strings.Replace(s[at+1:], ":", "/", 1)
+	}
+	s = strings.TrimRight(s, "/")
+	s = strings.TrimSuffix(s, ".git")
+	return strings.ToLower(s)
+}
+
+func normalizeProviderURL(providerURL string) string {
+	return strings.ToLower(strings.TrimRight(strings.TrimSpace(providerURL), "/"))
+}
@@ -0,0 +1,87 @@
+package wharfapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeGitURL(t *testing.T) {
+	tests := []struct {
+		name   string
+		gitURL string
+	}{
+		{"scp-like", "git@github.com:iver-wharf/wharf-api.git"},
+		{"https", "https://github.com/iver-wharf/wharf-api"},
+		{"https with .git", "https://github.com/iver-wharf/wharf-api.git"},
+		{"https with user", "https://user@GitHub.com/iver-wharf/wharf-api.git"},
+		{"ssh with port", "ssh://git@github.com:22/iver-wharf/wharf-api/"},
+		{"no scheme", "github.com/iver-wharf/wharf-api"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, "github.com/iver-wharf/wharf-api", NormalizeGitURL(tc.gitURL))
+		})
+	}
+}
+
+func TestFindProjectByGitURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "wharf-api", r.URL.Query().Get("gitUrlMatch"))
+		fmt.Fprint(w, `{"totalCount":2,"list":[
+			{"projectId":1,"gitUrl":"git@github.com:iver-wharf/wharf-api-client-go.git"},
+			{"projectId":2,"gitUrl":"git@github.com:iver-wharf/wharf-api.git"}]}`)
+	}))
+	defer srv.Close()
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+
+	project, err := c.FindProjectByGitURL(context.Background(), "https://github.com/iver-wharf/wharf-api")
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), project.ProjectID)
+}
+
+func TestFindProject_notFoundAndAmbiguous(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("name") == "missing" {
+			fmt.Fprint(w, `{"totalCount":0,"list":[]}`)
+			return
+		}
+		fmt.Fprint(w, `{"totalCount":3,"list":[
+			{"projectId":1,"groupName":"team","name":"api"},
+			{"projectId":2,"groupName":"team","name":"api"},
+			{"projectId":3,"groupName":"team","name":"API"}]}`)
+	}))
+	defer srv.Close()
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+
+	_, err := c.FindProject(context.Background(), "team", "missing")
+	var notFound *NotFoundError
+	require.True(t, errors.As(err, &notFound))
+	assert.Equal(t, "project", notFound.Kind)
+
+	_, err = c.FindProject(context.Background(), "team", "api")
+	var ambiguous *AmbiguousMatchError
+	require.True(t, errors.As(err, &ambiguous))
+	assert.Equal(t, []uint{1, 2}, ambiguous.IDs)
+}
+
+func TestFindProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "gitlab", r.URL.Query().Get("name"))
+		fmt.Fprint(w, `{"totalCount":2,"list":[
+			{"providerId":1,"name":"gitlab","url":"https://gitlab.com"},
+			{"providerId":2,"name":"gitlab","url":"https://gitlab.example.com/"}]}`)
+	}))
+	defer srv.Close()
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+
+	provider, err := c.FindProvider(context.Background(), "gitlab", "https://GitLab.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), provider.ProviderID)
+}
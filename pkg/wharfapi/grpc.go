@@ -93,6 +93,7 @@ func (c *Client) grpcDial() (*grpc.ClientConn, error) {
 		})
 		opts = append(opts, grpc.WithPerRPCCredentials(perRPC))
 	}
+	opts = append(opts, c.GRPCDialOptions...)
 
 	trimmed := strings.TrimRight(trimProtocol(c.APIURL), "/")
 	if !hasPortSuffixRegexp.MatchString(trimmed) {
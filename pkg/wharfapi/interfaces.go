@@ -0,0 +1,145 @@
+package wharfapi
+
+import (
+	"context"
+	"io"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/request"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/testresult"
+	"github.com/iver-wharf/wharf-core/pkg/app"
+)
+
+// Compile-time checks that the Client implements all interfaces.
+var (
+	_ API         = (*Client)(nil)
+	_ Builds      = (*Client)(nil)
+	_ Projects    = (*Client)(nil)
+	_ Branches    = (*Client)(nil)
+	_ Artifacts   = (*Client)(nil)
+	_ TestResults = (*Client)(nil)
+	_ Providers   = (*Client)(nil)
+	_ Tokens      = (*Client)(nil)
+	_ Meta        = (*Client)(nil)
+)
+
+// API is the full set of wharf-api methods of the Client, so that code using
+// the Client can be tested using a mock, such as the wharfapimock.Client.
+//
+// The Client's methods for configuring its caches, such as
+// Client.SetCachedVersion, are not included.
+type API interface {
+	Builds
+	Projects
+	Branches
+	Artifacts
+	TestResults
+	Providers
+	Tokens
+	Meta
+}
+
+// Builds is the set of Client methods for builds and their logs.
+type Builds interface {
+	GetBuildList(params BuildSearch) (response.PaginatedBuilds, error)
+	GetBuild(buildID uint) (response.Build, error)
+	UpdateBuildStatus(buildID uint, status request.LogOrStatusUpdate) (response.Build, error)
+	CreateBuildLog(buildID uint, buildLog request.LogOrStatusUpdate) error
+	GetBuildLogList(buildID uint) ([]response.Log, error)
+	CreateBuildLogStream(ctx context.Context) (CreateBuildLogStream, error)
+	StartProjectBuild(projectID uint, params ProjectStartBuild, inputs request.BuildInputs) (response.BuildReferenceWrapper, error)
+}
+
+// Projects is the set of Client methods for projects and their overrides.
+type Projects interface {
+	CreateProject(project request.Project) (response.Project, error)
+	GetProject(projectID uint) (response.Project, error)
+	GetProjectList(params ProjectSearch) (response.PaginatedProjects, error)
+	GetProjectListAll(params ProjectSearch) ([]response.Project, error)
+	UpdateProject(projectID uint, project request.ProjectUpdate) (response.Project, error)
+	PatchProject(ctx context.Context, projectID uint, mutate func(*request.ProjectUpdate)) (response.Project, error)
+	DeleteProject(projectID uint) error
+	PreviewDeleteProject(projectID uint) (ProjectDeletePreview, error)
+	DeleteProjectConfirmed(projectID uint, confirmationToken string, opts ProjectDeleteOptions) error
+	GetProjectOverrides(projectID uint) (response.ProjectOverrides, error)
+	UpdateProjectOverrides(projectID uint, overrides request.ProjectOverridesUpdate) (response.ProjectOverrides, error)
+	DeleteProjectOverrides(projectID uint) error
+	GetEffectiveProject(projectID uint) (EffectiveProject, error)
+	ResetProjectOverrideFields(projectID uint, fields ...ProjectOverrideField) (response.ProjectOverrides, error)
+	EnsureProject(ctx context.Context, project request.Project) (response.Project, EnsureOutcome, error)
+	FindProject(ctx context.Context, groupName, name string) (response.Project, error)
+	FindProjectByGitURL(ctx context.Context, gitURL string) (response.Project, error)
+}
+
+// Branches is the set of Client methods for project branches.
+type Branches interface {
+	CreateProjectBranch(projectID uint, branch request.Branch) (response.Branch, error)
+	UpdateProjectBranchList(projectID uint, branches []request.Branch) ([]response.Branch, error)
+	GetProjectBranchList(projectID uint) ([]response.Branch, error)
+	EnsureBranches(ctx context.Context, projectID uint, branches []request.Branch) ([]response.Branch, EnsureOutcome, error)
+}
+
+// Artifacts is the set of Client methods for build artifacts.
+type Artifacts interface {
+	GetBuildArtifactList(params ArtifactSearch, buildID uint) (response.PaginatedArtifacts, error)
+	GetBuildArtifactListAll(params ArtifactSearch, buildID uint) ([]response.Artifact, error)
+	GetBuildArtifact(buildID, artifactID uint) (io.ReadCloser, error)
+	DownloadBuildArtifact(ctx context.Context, buildID, artifactID uint, dst string, opts ArtifactDownloadOptions) (ArtifactDownload, error)
+	DownloadBuildArtifactList(ctx context.Context, buildID uint, dir string, opts BuildArtifactsDownloadOptions) (ArtifactManifest, error)
+	CreateBuildArtifact(buildID uint, fileName string, artifact io.Reader) error
+	CreateBuildArtifactList(ctx context.Context, buildID uint, files []ArtifactFile, opts ArtifactUploadOptions) ([]response.ArtifactMetadata, error)
+	CreateBuildArtifactDir(ctx context.Context, buildID uint, dir string, opts ArtifactDirOptions) ([]response.ArtifactMetadata, error)
+}
+
+// TestResults is the set of Client methods for build test results.
+type TestResults interface {
+	GetBuildAllTestResultDetailList(buildID uint) (response.PaginatedTestResultDetails, error)
+	SearchBuildAllTestResultDetailList(params TestResultDetailSearch, buildID uint) (response.PaginatedTestResultDetails, error)
+	IterateBuildAllTestResultDetails(params TestResultDetailSearch, buildID uint) *TestResultDetailIterator
+	GetBuildAllTestResultSummaryList(buildID uint) (response.PaginatedTestResultSummaries, error)
+	GetBuildTestResultSummary(buildID, artifactID uint) (response.TestResultSummary, error)
+	GetBuildTestResultDetailList(buildID, artifactID uint) (response.PaginatedTestResultDetails, error)
+	SearchBuildTestResultDetailList(params TestResultDetailSearch, buildID, artifactID uint) (response.PaginatedTestResultDetails, error)
+	IterateBuildTestResultDetails(params TestResultDetailSearch, buildID, artifactID uint) *TestResultDetailIterator
+	GetBuildAllTestResultListSummary(buildID uint) (response.TestResultListSummary, error)
+	GetBuildTestResultReport(buildID uint) (testresult.Report, error)
+	CreateBuildTestResult(buildID uint, fileName string, testResult io.Reader) ([]response.ArtifactMetadata, error)
+	CreateBuildTestResultFrom(ctx context.Context, buildID uint, format testresult.Format, testResult io.Reader) ([]response.ArtifactMetadata, error)
+	CompareBuildTestResults(baseBuildID, headBuildID uint, opts testresult.CompareOptions) (testresult.Diff, error)
+	FindFlakyTests(params FlakyTestSearch) (FlakyTestReport, error)
+}
+
+// Providers is the set of Client methods for providers.
+type Providers interface {
+	CreateProvider(provider request.Provider) (response.Provider, error)
+	GetProvider(providerID uint) (response.Provider, error)
+	GetProviderList(params ProviderSearch) (response.PaginatedProviders, error)
+	GetProviderListAll(params ProviderSearch) ([]response.Provider, error)
+	UpdateProvider(providerID uint, provider request.ProviderUpdate) (response.Provider, error)
+	PatchProvider(ctx context.Context, providerID uint, mutate func(*request.ProviderUpdate)) (response.Provider, error)
+	EnsureProvider(ctx context.Context, provider request.Provider) (response.Provider, EnsureOutcome, error)
+	FindProvider(ctx context.Context, name, providerURL string) (response.Provider, error)
+}
+
+// Tokens is the set of Client methods for tokens.
+type Tokens interface {
+	CreateToken(token request.Token) (response.Token, error)
+	GetToken(tokenID uint) (response.Token, error)
+	GetTokenList(params TokenSearch) (response.PaginatedTokens, error)
+	GetTokenListAll(params TokenSearch) ([]response.Token, error)
+	UpdateToken(tokenID uint, token request.TokenUpdate) (response.Token, error)
+	PatchToken(ctx context.Context, tokenID uint, mutate func(*request.TokenUpdate)) (response.Token, error)
+	EnsureToken(ctx context.Context, token request.Token) (response.Token, EnsureOutcome, error)
+	FindToken(ctx context.Context, userName string) (response.Token, error)
+	RotateToken(ctx context.Context, tokenID uint, opts TokenRotationOptions) (TokenRotationReport, error)
+}
+
+// Meta is the set of Client methods for the wharf-api itself, such as its
+// version, health, and execution engines.
+type Meta interface {
+	GetVersion() (app.Version, error)
+	GetHealth() (response.HealthStatus, error)
+	Ping() (response.Ping, error)
+	GetEngineList() (response.EngineList, error)
+	ResolveEngine(idOrName string) (response.Engine, error)
+}
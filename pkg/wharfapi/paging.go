@@ -0,0 +1,34 @@
+package wharfapi
+
+// defaultPageSize is the number of items fetched per request when paging
+// through all items of an endpoint, unless a limit was explicitly given.
+const defaultPageSize = 100
+
+// fetchPageFunc fetches a single page, and returns the number of items in
+// that page as well as the total count reported by the wharf-api.
+type fetchPageFunc func(limit, offset int) (count int, totalCount int64, err error)
+
+// fetchAllPages calls the fetch function repeatedly with increasing offsets
+// until all items have been fetched. The limit argument is used as page size,
+// and defaults to defaultPageSize if nil or not positive. The offset argument
+// is used as starting offset, and defaults to zero if nil.
+func fetchAllPages(limit, offset *int, fetch fetchPageFunc) error {
+	pageSize := defaultPageSize
+	if limit != nil && *limit > 0 {
+		pageSize = *limit
+	}
+	nextOffset := 0
+	if offset != nil && *offset > 0 {
+		nextOffset = *offset
+	}
+	for {
+		count, totalCount, err := fetch(pageSize, nextOffset)
+		if err != nil {
+			return err
+		}
+		nextOffset += count
+		if count == 0 || int64(nextOffset) >= totalCount {
+			return nil
+		}
+	}
+}
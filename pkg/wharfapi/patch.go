@@ -0,0 +1,194 @@
+package wharfapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/request"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+)
+
+// maxPatchAttempts is the number of times a patch is attempted before giving
+// up due to concurrent modifications.
+const maxPatchAttempts = 3
+
+// ErrPatchConflict is returned when a patch could not be applied because the
+// object kept being modified concurrently.
+var ErrPatchConflict = errors.New("concurrent modification")
+
+// PatchProject updates only the fields changed by the mutate function, by
+// fetching the project, applying the mutation to its current state, and then
+// invoking the HTTP requests:
+//  GET /api/project/{projectId}
+//  PUT /api/project/{projectId}
+//
+// The project is fetched a second time right before the update, and if its
+// UpdatedAt timestamp has changed in between, then the mutation is retried on
+// the new state. This narrows, but does not fully close, the window where a
+// concurrent edit could be overwritten. As the mutation may be applied
+// multiple times, it must not have any side effects.
+//
+// No update is made if the mutation did not change anything, in which case
+// the current project is returned.
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) PatchProject(ctx context.Context, projectID uint, mutate func(*request.ProjectUpdate)) (response.Project, error) {
+	if err := c.validateEndpointVersion(5, 0, 0); err != nil {
+		return response.Project{}, err
+	}
+	path := fmt.Sprintf("/api/project/%d", projectID)
+	var result response.Project
+	err := retryPatch(ctx, "project", projectID, func() (bool, error) {
+		var current response.Project
+		if err := c.getUnmarshalContext(ctx, path, nil, &current); err != nil {
+			return false, err
+		}
+		orig := request.ProjectUpdate{
+			Name:            current.Name,
+			GroupName:       current.GroupName,
+			Description:     current.Description,
+			AvatarURL:       current.AvatarURL,
+			TokenID:         current.TokenID,
+			ProviderID:      current.ProviderID,
+			BuildDefinition: current.BuildDefinition,
+			GitURL:          current.GitURL,
+		}
+		update := orig
+		mutate(&update)
+		if update == orig {
+			result = current
+			return false, nil
+		}
+		var latest response.Project
+		if err := c.getUnmarshalContext(ctx, path, nil, &latest); err != nil {
+			return false, err
+		}
+		if !isSameUpdatedAt(current.TimeMetadata, latest.TimeMetadata) {
+			return true, nil
+		}
+		return false, c.putJSONUnmarshalContext(ctx, path, nil, update, &result)
+	})
+	return result, err
+}
+
+// PatchProvider updates only the fields changed by the mutate function, by
+// fetching the provider, applying the mutation to its current state, and then
+// invoking the HTTP requests:
+//  GET /api/provider/{providerId}
+//  PUT /api/provider/{providerId}
+//
+// Concurrent modifications are handled the same way as in PatchProject.
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) PatchProvider(ctx context.Context, providerID uint, mutate func(*request.ProviderUpdate)) (response.Provider, error) {
+	if err := c.validateEndpointVersion(5, 0, 0); err != nil {
+		return response.Provider{}, err
+	}
+	path := fmt.Sprintf("/api/provider/%d", providerID)
+	var result response.Provider
+	err := retryPatch(ctx, "provider", providerID, func() (bool, error) {
+		var current response.Provider
+		if err := c.getUnmarshalContext(ctx, path, nil, &current); err != nil {
+			return false, err
+		}
+		orig := request.ProviderUpdate{
+			Name:    request.ProviderName(current.Name),
+			URL:     current.URL,
+			TokenID: current.TokenID,
+		}
+		update := orig
+		mutate(&update)
+		if update == orig {
+			result = current
+			return false, nil
+		}
+		var latest response.Provider
+		if err := c.getUnmarshalContext(ctx, path, nil, &latest); err != nil {
+			return false, err
+		}
+		if !isSameUpdatedAt(current.TimeMetadata, latest.TimeMetadata) {
+			return true, nil
+		}
+		return false, c.putJSONUnmarshalContext(ctx, path, nil, update, &result)
+	})
+	return result, err
+}
+
+// PatchToken updates only the fields changed by the mutate function, by
+// fetching the token, applying the mutation to its current state, and then
+// invoking the HTTP requests:
+//  GET /api/token/{tokenId}
+//  PUT /api/token/{tokenId}
+//
+// Concurrent modifications are handled the same way as in PatchProject.
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) PatchToken(ctx context.Context, tokenID uint, mutate func(*request.TokenUpdate)) (response.Token, error) {
+	if err := c.validateEndpointVersion(5, 0, 0); err != nil {
+		return response.Token{}, err
+	}
+	path := fmt.Sprintf("/api/token/%d", tokenID)
+	var result response.Token
+	err := retryPatch(ctx, "token", tokenID, func() (bool, error) {
+		var current response.Token
+		if err := c.getUnmarshalContext(ctx, path, nil, &current); err != nil {
+			return false, err
+		}
+		orig := request.TokenUpdate{
+			Token:    current.Token,
+			UserName: current.UserName,
+		}
+		update := orig
+		mutate(&update)
+		if update == orig {
+			result = current
+			return false, nil
+		}
+		var latest response.Token
+		if err := c.getUnmarshalContext(ctx, path, nil, &latest); err != nil {
+			return false, err
+		}
+		if !isSameUpdatedAt(current.TimeMetadata, latest.TimeMetadata) {
+			return true, nil
+		}
+		return false, c.putJSONUnmarshalContext(ctx, path, nil, update, &result)
+	})
+	return result, err
+}
+
+// retryPatch calls the attempt function until it reports no conflict, or
+// until maxPatchAttempts is reached.
+func retryPatch(ctx context.Context, kind string, id uint, attempt func() (conflict bool, err error)) error {
+	for i := 0; i < maxPatchAttempts; i++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		conflict, err := attempt()
+		if err != nil {
+			return fmt.Errorf("patch %s %d: %w", kind, id, err)
+		}
+		if !conflict {
+			return nil
+		}
+		log.Debug().
+			WithString("kind", kind).
+			WithUint("id", id).
+			WithInt("attempt", i+1).
+			Message("Concurrent modification detected, retrying patch.")
+	}
+	return fmt.Errorf("patch %s %d: %w, gave up after %d attempts",
+		kind, id, ErrPatchConflict, maxPatchAttempts)
+}
+
+func isSameUpdatedAt(a, b response.TimeMetadata) bool {
+	return isSameTime(a.UpdatedAt, b.UpdatedAt)
+}
+
+func isSameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
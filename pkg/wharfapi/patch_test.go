@@ -0,0 +1,92 @@
+package wharfapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/request"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchProject(t *testing.T) {
+	var gets, puts int
+	var put request.ProjectUpdate
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/project/5", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			gets++
+			// Simulate a concurrent edit between the first two fetches.
+			updatedAt := "2022-05-01T12:00:00Z"
+			if gets > 1 {
+				updatedAt = "2022-05-01T12:30:00Z"
+			}
+			fmt.Fprintf(w, `{"projectId":5,"name":"api","groupName":"team","description":"old","tokenId":2,"updatedAt":%q}`, updatedAt)
+		case http.MethodPut:
+			puts++
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&put))
+			fmt.Fprintf(w, `{"projectId":5,"name":%q,"description":%q}`, put.Name, put.Description)
+		}
+	}))
+	defer srv.Close()
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+
+	var mutations int
+	project, err := c.PatchProject(context.Background(), 5, func(p *request.ProjectUpdate) {
+		mutations++
+		p.Description = "new"
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "new", project.Description)
+	assert.Equal(t, 2, mutations, "mutation should be retried after conflict")
+	assert.Equal(t, 4, gets)
+	assert.Equal(t, 1, puts)
+	assert.Equal(t, request.ProjectUpdate{
+		Name:        "api",
+		GroupName:   "team",
+		Description: "new",
+		TokenID:     2,
+	}, put)
+}
+
+func TestPatchProject_noChange(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		fmt.Fprint(w, `{"projectId":5,"name":"api","description":"same"}`)
+	}))
+	defer srv.Close()
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+
+	project, err := c.PatchProject(context.Background(), 5, func(p *request.ProjectUpdate) {
+		p.Description = "same"
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "api", project.Name)
+}
+
+func TestPatchToken_conflict(t *testing.T) {
+	var gets int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !assert.Equal(t, http.MethodGet, r.Method, "should never write on conflict") {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		gets++
+		fmt.Fprintf(w, `{"tokenId":3,"userName":"bot","updatedAt":"2022-05-01T12:00:%02dZ"}`, gets)
+	}))
+	defer srv.Close()
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+
+	_, err := c.PatchToken(context.Background(), 3, func(tok *request.TokenUpdate) {
+		tok.UserName = "other"
+	})
+	assert.True(t, errors.Is(err, ErrPatchConflict))
+	assert.Equal(t, 2*maxPatchAttempts, gets)
+}
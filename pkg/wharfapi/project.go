@@ -1,6 +1,7 @@
 package wharfapi
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/google/go-querystring/query"
@@ -76,6 +77,37 @@ func (c *Client) GetProjectList(params ProjectSearch) (response.PaginatedProject
 	return projects, err
 }
 
+// GetProjectListAll fetches all projects matching the parameters by repeatedly
+// invoking the HTTP request:
+//  GET /api/project
+//
+// The ProjectSearch.Limit field is used as page size, and defaults to 100.
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) GetProjectListAll(params ProjectSearch) ([]response.Project, error) {
+	return c.listProjects(context.Background(), params)
+}
+
+// listProjects fetches all projects matching the parameters, one page at a
+// time. Used by GetProjectListAll and the context-aware methods.
+func (c *Client) listProjects(ctx context.Context, params ProjectSearch) ([]response.Project, error) {
+	if err := c.validateEndpointVersion(5, 0, 0); err != nil {
+		return nil, err
+	}
+	var projects []response.Project
+	err := fetchAllPages(params.Limit, params.Offset, func(limit, offset int) (int, int64, error) {
+		params.Limit = &limit
+		params.Offset = &offset
+		var page response.PaginatedProjects
+		if err := c.listContext(ctx, "/api/project", params, &page); err != nil {
+			return 0, 0, err
+		}
+		projects = append(projects, page.List...)
+		return len(page.List), page.TotalCount, nil
+	})
+	return projects, err
+}
+
 // UpdateProject updates a project by ID by invoking the HTTP request:
 //  PUT /api/project/{projectID}
 //
@@ -91,10 +123,11 @@ func (c *Client) UpdateProject(projectID uint, project request.ProjectUpdate) (r
 }
 
 // DeleteProject deletes a project by ID by invoking the HTTP request:
-//  DELETE /api/project/{projectID}/override
+//  DELETE /api/project/{projectID}
 //
 // This will also delete all associated artifacts, builds, and logs. This is an
-// irreversable action.
+// irreversable action. See PreviewDeleteProject and DeleteProjectConfirmed for
+// a safeguarded alternative.
 //
 // Added in wharf-api v0.2.8.
 func (c *Client) DeleteProject(projectID uint) error {
@@ -0,0 +1,127 @@
+package wharfapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+)
+
+// ErrDeleteConfirmationMismatch is returned by DeleteProjectConfirmed when the
+// confirmation token does not match the project's current delete preview,
+// such as when a new build has been started since the preview was made.
+var ErrDeleteConfirmationMismatch = errors.New("delete confirmation token mismatch")
+
+// ProjectDeletePreview is the impact of deleting a project, as everything
+// listed here is deleted together with the project.
+type ProjectDeletePreview struct {
+	Project       response.Project
+	BuildCount    int64
+	ArtifactCount int64
+	// LatestBuild is nil if the project has no builds.
+	LatestBuild *response.Build
+	// ConfirmationToken is derived from the preview, and is required by
+	// DeleteProjectConfirmed.
+	ConfirmationToken string
+}
+
+// ProjectDeleteOptions holds optional settings used when deleting a project
+// through DeleteProjectConfirmed.
+type ProjectDeleteOptions struct {
+	// BeforeDelete, if set, is called with the verified preview right before
+	// the project is deleted, such as to export the project first. Returning
+	// an error aborts the deletion.
+	BeforeDelete func(preview ProjectDeletePreview) error
+}
+
+// PreviewDeleteProject reports what would be deleted together with the
+// project, by invoking the HTTP requests:
+//  GET /api/project/{projectId}
+//  GET /api/build?projectId={projectId}
+//  GET /api/build/{buildId}/artifact
+//
+// The artifacts are counted per build, so the number of requests grows with
+// the number of builds.
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) PreviewDeleteProject(projectID uint) (ProjectDeletePreview, error) {
+	var preview ProjectDeletePreview
+	project, err := c.GetProject(projectID)
+	if err != nil {
+		return preview, fmt.Errorf("get project: %w", err)
+	}
+	preview.Project = project
+
+	search := BuildSearch{
+		OrderBy:   []string{response.BuildJSONFields.BuildID + " desc"},
+		ProjectID: &projectID,
+	}
+	err = fetchAllPages(nil, nil, func(limit, offset int) (int, int64, error) {
+		search.Limit = &limit
+		search.Offset = &offset
+		page, err := c.GetBuildList(search)
+		if err != nil {
+			return 0, 0, fmt.Errorf("get build list: %w", err)
+		}
+		if offset == 0 && len(page.List) > 0 {
+			latest := page.List[0]
+			preview.LatestBuild = &latest
+		}
+		preview.BuildCount = page.TotalCount
+		for _, build := range page.List {
+			one := 1
+			artifacts, err := c.GetBuildArtifactList(ArtifactSearch{Limit: &one}, build.BuildID)
+			if err != nil {
+				return 0, 0, fmt.Errorf("get artifacts of build %d: %w", build.BuildID, err)
+			}
+			preview.ArtifactCount += artifacts.TotalCount
+		}
+		return len(page.List), page.TotalCount, nil
+	})
+	if err != nil {
+		return preview, err
+	}
+	preview.ConfirmationToken = newDeleteConfirmationToken(preview)
+	return preview, nil
+}
+
+// DeleteProjectConfirmed deletes a project by ID, but only if the confirmation
+// token matches a new preview of the project, by invoking the same HTTP
+// requests as PreviewDeleteProject, followed by:
+//  DELETE /api/project/{projectId}
+//
+// This will also delete all associated artifacts, builds, and logs. This is an
+// irreversible action.
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) DeleteProjectConfirmed(projectID uint, confirmationToken string, opts ProjectDeleteOptions) error {
+	preview, err := c.PreviewDeleteProject(projectID)
+	if err != nil {
+		return err
+	}
+	if confirmationToken != preview.ConfirmationToken {
+		return fmt.Errorf("delete project %d: %w", projectID, ErrDeleteConfirmationMismatch)
+	}
+	if opts.BeforeDelete != nil {
+		if err := opts.BeforeDelete(preview); err != nil {
+			return fmt.Errorf("delete project %d: before delete: %w", projectID, err)
+		}
+	}
+	return c.DeleteProject(projectID)
+}
+
+// newDeleteConfirmationToken derives a short token from the parts of the
+// preview that change when something is added to the project, so that a
+// token only confirms the deletion of what was previewed.
+func newDeleteConfirmationToken(preview ProjectDeletePreview) string {
+	var latestBuildID uint
+	if preview.LatestBuild != nil {
+		latestBuildID = preview.LatestBuild.BuildID
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d\x00%s\x00%s\x00%d\x00%d\x00%d",
+		preview.Project.ProjectID, preview.Project.GroupName, preview.Project.Name,
+		preview.BuildCount, preview.ArtifactCount, latestBuildID)))
+	return hex.EncodeToString(sum[:6])
+}
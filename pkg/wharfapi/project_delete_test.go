@@ -0,0 +1,82 @@
+package wharfapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDeleteProjectServer(t *testing.T, deleted *bool, latestBuildID *int) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/api/project/5" && r.Method == http.MethodGet:
+			fmt.Fprint(w, `{"projectId":5,"name":"api","groupName":"team"}`)
+		case r.URL.Path == "/api/project/5" && r.Method == http.MethodDelete:
+			*deleted = true
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/api/build":
+			assert.Equal(t, "5", r.URL.Query().Get("projectId"))
+			fmt.Fprintf(w, `{"totalCount":2,"list":[{"buildId":%d},{"buildId":7}]}`, *latestBuildID)
+		case r.URL.Path == "/api/build/7/artifact":
+			fmt.Fprint(w, `{"totalCount":3,"list":[{"artifactId":1}]}`)
+		default:
+			fmt.Fprint(w, `{"totalCount":1,"list":[{"artifactId":2}]}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestDeleteProjectConfirmed(t *testing.T) {
+	var deleted bool
+	latestBuildID := 8
+	srv := newTestDeleteProjectServer(t, &deleted, &latestBuildID)
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+
+	preview, err := c.PreviewDeleteProject(5)
+	require.NoError(t, err)
+	assert.Equal(t, "api", preview.Project.Name)
+	assert.Equal(t, int64(2), preview.BuildCount)
+	assert.Equal(t, int64(4), preview.ArtifactCount)
+	require.NotNil(t, preview.LatestBuild)
+	assert.Equal(t, uint(8), preview.LatestBuild.BuildID)
+	assert.NotEmpty(t, preview.ConfirmationToken)
+
+	err = c.DeleteProjectConfirmed(5, "wrong", ProjectDeleteOptions{})
+	assert.True(t, errors.Is(err, ErrDeleteConfirmationMismatch))
+	assert.False(t, deleted)
+
+	var hookCalled bool
+	err = c.DeleteProjectConfirmed(5, preview.ConfirmationToken, ProjectDeleteOptions{
+		BeforeDelete: func(p ProjectDeletePreview) error {
+			hookCalled = true
+			return errors.New("export failed")
+		},
+	})
+	assert.Error(t, err)
+	assert.True(t, hookCalled)
+	assert.False(t, deleted)
+
+	require.NoError(t, c.DeleteProjectConfirmed(5, preview.ConfirmationToken, ProjectDeleteOptions{}))
+	assert.True(t, deleted)
+}
+
+func TestDeleteProjectConfirmed_newBuildSincePreview(t *testing.T) {
+	var deleted bool
+	latestBuildID := 8
+	srv := newTestDeleteProjectServer(t, &deleted, &latestBuildID)
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+
+	preview, err := c.PreviewDeleteProject(5)
+	require.NoError(t, err)
+	latestBuildID = 9
+	err = c.DeleteProjectConfirmed(5, preview.ConfirmationToken, ProjectDeleteOptions{})
+	assert.True(t, errors.Is(err, ErrDeleteConfirmationMismatch))
+	assert.False(t, deleted)
+}
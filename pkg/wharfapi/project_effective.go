@@ -0,0 +1,137 @@
+package wharfapi
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/request"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+)
+
+// ProjectOverrideField is an enum of the project fields that can be
+// overridden, using the same names as the JSON fields.
+type ProjectOverrideField string
+
+const (
+	// ProjectOverrideDescription is the overridable project description.
+	ProjectOverrideDescription ProjectOverrideField = "description"
+	// ProjectOverrideAvatarURL is the overridable project avatar URL.
+	ProjectOverrideAvatarURL ProjectOverrideField = "avatarUrl"
+	// ProjectOverrideGitURL is the overridable project Git URL.
+	ProjectOverrideGitURL ProjectOverrideField = "gitUrl"
+)
+
+// EffectiveProject is a project with its overrides applied, where non-empty
+// override fields win over the project's own fields.
+type EffectiveProject struct {
+	// Project holds the merged fields.
+	Project response.Project
+	// Original holds the project's own fields, before the overrides were
+	// applied.
+	Original  response.Project
+	Overrides response.ProjectOverrides
+	// OverriddenFields lists the fields whose values came from the overrides.
+	OverriddenFields []ProjectOverrideField
+}
+
+// IsOverridden returns true if the field's value came from the overrides.
+func (p EffectiveProject) IsOverridden(field ProjectOverrideField) bool {
+	for _, f := range p.OverriddenFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// MergeProjectOverrides applies the non-empty override fields to the project.
+func MergeProjectOverrides(project response.Project, overrides response.ProjectOverrides) EffectiveProject {
+	effective := EffectiveProject{
+		Project:   project,
+		Original:  project,
+		Overrides: overrides,
+	}
+	if overrides.Description != "" {
+		effective.Project.Description = overrides.Description
+		effective.OverriddenFields = append(effective.OverriddenFields, ProjectOverrideDescription)
+	}
+	if overrides.AvatarURL != "" {
+		effective.Project.AvatarURL = overrides.AvatarURL
+		effective.OverriddenFields = append(effective.OverriddenFields, ProjectOverrideAvatarURL)
+	}
+	if overrides.GitURL != "" {
+		effective.Project.GitURL = overrides.GitURL
+		effective.OverriddenFields = append(effective.OverriddenFields, ProjectOverrideGitURL)
+	}
+	return effective
+}
+
+// GetEffectiveProject fetches a project and its overrides concurrently, and
+// merges them, by invoking the HTTP requests:
+//  GET /api/project/{projectId}
+//  GET /api/project/{projectId}/override
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) GetEffectiveProject(projectID uint) (EffectiveProject, error) {
+	// Validated once up front, as the cached version is not safe for
+	// concurrent use.
+	if err := c.validateEndpointVersion(5, 0, 0); err != nil {
+		return EffectiveProject{}, err
+	}
+	var (
+		wg                      sync.WaitGroup
+		project                 response.Project
+		overrides               response.ProjectOverrides
+		projectErr, overrideErr error
+	)
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		projectErr = c.getUnmarshal(fmt.Sprintf("/api/project/%d", projectID), nil, &project)
+	}()
+	go func() {
+		defer wg.Done()
+		overrideErr = c.getUnmarshal(fmt.Sprintf("/api/project/%d/override", projectID), nil, &overrides)
+	}()
+	wg.Wait()
+	if projectErr != nil {
+		return EffectiveProject{}, fmt.Errorf("get project: %w", projectErr)
+	}
+	if overrideErr != nil {
+		return EffectiveProject{}, fmt.Errorf("get project overrides: %w", overrideErr)
+	}
+	return MergeProjectOverrides(project, overrides), nil
+}
+
+// ResetProjectOverrideFields clears the given override fields of a project,
+// while keeping its other override fields, by invoking the HTTP requests:
+//  GET /api/project/{projectId}/override
+//  PUT /api/project/{projectId}/override
+//
+// Use DeleteProjectOverrides to clear all override fields.
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) ResetProjectOverrideFields(projectID uint, fields ...ProjectOverrideField) (response.ProjectOverrides, error) {
+	overrides, err := c.GetProjectOverrides(projectID)
+	if err != nil {
+		return response.ProjectOverrides{}, err
+	}
+	update := request.ProjectOverridesUpdate{
+		Description: overrides.Description,
+		AvatarURL:   overrides.AvatarURL,
+		GitURL:      overrides.GitURL,
+	}
+	for _, field := range fields {
+		switch field {
+		case ProjectOverrideDescription:
+			update.Description = ""
+		case ProjectOverrideAvatarURL:
+			update.AvatarURL = ""
+		case ProjectOverrideGitURL:
+			update.GitURL = ""
+		default:
+			return overrides, fmt.Errorf("reset project overrides: unknown field %q", field)
+		}
+	}
+	return c.UpdateProjectOverrides(projectID, update)
+}
@@ -0,0 +1,71 @@
+package wharfapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/request"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeProjectOverrides(t *testing.T) {
+	project := response.Project{
+		Description: "original",
+		AvatarURL:   "https://example.com/original.png",
+		GitURL:      "git@example.com:original.git",
+	}
+	effective := MergeProjectOverrides(project, response.ProjectOverrides{
+		Description: "overridden",
+	})
+	assert.Equal(t, "overridden", effective.Project.Description)
+	assert.Equal(t, project.AvatarURL, effective.Project.AvatarURL)
+	assert.Equal(t, "original", effective.Original.Description)
+	assert.Equal(t, []ProjectOverrideField{ProjectOverrideDescription}, effective.OverriddenFields)
+	assert.True(t, effective.IsOverridden(ProjectOverrideDescription))
+	assert.False(t, effective.IsOverridden(ProjectOverrideGitURL))
+}
+
+func TestGetEffectiveProject(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/project/5":
+			fmt.Fprint(w, `{"projectId":5,"gitUrl":"git@example.com:a.git"}`)
+		case "/api/project/5/override":
+			fmt.Fprint(w, `{"projectId":5,"gitUrl":"git@mirror.example.com:a.git"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+
+	effective, err := c.GetEffectiveProject(5)
+	require.NoError(t, err)
+	assert.Equal(t, "git@mirror.example.com:a.git", effective.Project.GitURL)
+	assert.Equal(t, []ProjectOverrideField{ProjectOverrideGitURL}, effective.OverriddenFields)
+}
+
+func TestResetProjectOverrideFields(t *testing.T) {
+	var put request.ProjectOverridesUpdate
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/project/5/override", r.URL.Path)
+		if r.Method == http.MethodPut {
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&put))
+		}
+		fmt.Fprint(w, `{"projectId":5,"description":"a","avatarUrl":"b","gitUrl":"c"}`)
+	}))
+	defer srv.Close()
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+
+	_, err := c.ResetProjectOverrideFields(5, ProjectOverrideDescription, ProjectOverrideGitURL)
+	require.NoError(t, err)
+	assert.Equal(t, request.ProjectOverridesUpdate{AvatarURL: "b"}, put)
+
+	_, err = c.ResetProjectOverrideFields(5, "name")
+	assert.Error(t, err)
+}
@@ -1,6 +1,7 @@
 package wharfapi
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/google/go-querystring/query"
@@ -57,6 +58,37 @@ func (c *Client) GetProviderList(params ProviderSearch) (response.PaginatedProvi
 	return providers, err
 }
 
+// GetProviderListAll fetches all providers matching the parameters by repeatedly
+// invoking the HTTP request:
+//  GET /api/provider
+//
+// The ProviderSearch.Limit field is used as page size, and defaults to 100.
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) GetProviderListAll(params ProviderSearch) ([]response.Provider, error) {
+	return c.listProviders(context.Background(), params)
+}
+
+// listProviders fetches all providers matching the parameters, one page at a
+// time. Used by GetProviderListAll and the context-aware methods.
+func (c *Client) listProviders(ctx context.Context, params ProviderSearch) ([]response.Provider, error) {
+	if err := c.validateEndpointVersion(5, 0, 0); err != nil {
+		return nil, err
+	}
+	var providers []response.Provider
+	err := fetchAllPages(params.Limit, params.Offset, func(limit, offset int) (int, int64, error) {
+		params.Limit = &limit
+		params.Offset = &offset
+		var page response.PaginatedProviders
+		if err := c.listContext(ctx, "/api/provider", params, &page); err != nil {
+			return 0, 0, err
+		}
+		providers = append(providers, page.List...)
+		return len(page.List), page.TotalCount, nil
+	})
+	return providers, err
+}
+
 // UpdateProvider updates the provider with the specified ID by invoking the
 // HTTP request:
 //  PUT /api/provider/{providerID}
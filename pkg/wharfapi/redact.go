@@ -0,0 +1,224 @@
+package wharfapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Redactor removes sensitive values from URLs, HTTP headers, and JSON bodies
+// before they are logged.
+//
+// All names are matched case-insensitively and regardless of underscores and
+// dashes, so "access_token" also matches "accessToken" and "Access-Token".
+type Redactor struct {
+	// QueryKeys are URL query parameter names whose values are redacted.
+	QueryKeys []string
+	// HeaderNames are HTTP header names whose values are redacted. For the
+	// Authorization and Proxy-Authorization headers, the authentication
+	// scheme, such as "Bearer", is kept.
+	HeaderNames []string
+	// JSONFields are JSON object field names whose values are redacted, at
+	// any depth in the JSON body.
+	JSONFields []string
+}
+
+// DefaultRedactor returns the redactor used by a Client when its Redactor
+// field is nil. Useful as a starting point when adding more names.
+func DefaultRedactor() Redactor {
+	return Redactor{
+		QueryKeys: []string{
+			"token", "access_token", "private_token",
+			"password", "secret", "api_key",
+		},
+		HeaderNames: []string{
+			"Authorization", "Proxy-Authorization",
+			"Cookie", "Set-Cookie",
+			"Private-Token", "X-Api-Key",
+		},
+		JSONFields: []string{
+			"token", "accessToken", "refreshToken", "privateToken",
+			"password", "secret", "clientSecret", "apiKey",
+		},
+	}
+}
+
+var defaultRedactor = DefaultRedactor()
+
+// authSchemeHeaderNames are the headers whose values start with an
+// authentication scheme, which is kept when redacting.
+var authSchemeHeaderNames = []string{"Authorization", "Proxy-Authorization"}
+
+// URL returns the URL with the password in the user info and all sensitive
+// query parameter values redacted. The rest of the URL is kept as-is.
+//
+// If the user info cannot be parsed, then the whole URL is redacted, so
+// nothing sensitive is leaked on malformed URLs.
+func (r *Redactor) URL(urlStr string) string {
+	base, fragment, hasFragment := cutString(urlStr, '#')
+	base, rawQuery, hasQuery := cutString(base, '?')
+	if strings.Contains(base, "@") {
+		u, err := url.Parse(base)
+		if err != nil {
+			return redacted
+		}
+		if _, hasPassword := u.User.Password(); hasPassword {
+			base = redactUserInfoPassword(base)
+		}
+	}
+	var sb strings.Builder
+	sb.WriteString(base)
+	if hasQuery {
+		sb.WriteByte('?')
+		sb.WriteString(r.Query(rawQuery))
+	}
+	if hasFragment {
+		sb.WriteByte('#')
+		sb.WriteString(fragment)
+	}
+	return sb.String()
+}
+
+// redactUserInfoPassword replaces the password textually, as url.URL.String
+// would escape the redacted text.
+func redactUserInfoPassword(urlStr string) string {
+	slashes := strings.Index(urlStr, "//")
+	if slashes == -1 {
+		return redacted
+	}
+	authorityStart := slashes + len("//")
+	authorityEnd := len(urlStr)
+	if i := strings.IndexByte(urlStr[authorityStart:], '/'); i != -1 {
+		authorityEnd = authorityStart + i
+	}
+	authority := urlStr[authorityStart:authorityEnd]
+	at := strings.LastIndexByte(authority, '@')
+	if at == -1 {
+		return redacted
+	}
+	colon := strings.IndexByte(authority[:at], ':')
+	if colon == -1 {
+		return redacted
+	}
+	return urlStr[:authorityStart] + authority[:colon+1] + redacted + authority[at:] + urlStr[authorityEnd:]
+}
+
+// Query returns the URL-encoded query, such as "a=1&token=xyz", with the
+// values of all sensitive parameters redacted. Can also be used on
+// application/x-www-form-urlencoded bodies.
+func (r *Redactor) Query(rawQuery string) string {
+	params := strings.Split(rawQuery, "&")
+	for i, param := range params {
+		key, _, _ := cutString(param, '=')
+		unescaped, err := url.QueryUnescape(key)
+		if err != nil {
+			unescaped = key
+		}
+		if matchesRedactName(r.QueryKeys, unescaped) {
+			params[i] = key + "=" + redacted
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// Header returns a copy of the HTTP headers with all sensitive values
+// redacted.
+func (r *Redactor) Header(header http.Header) http.Header {
+	clone := header.Clone()
+	for name, values := range clone {
+		if !matchesRedactName(r.HeaderNames, name) {
+			continue
+		}
+		keepScheme := matchesRedactName(authSchemeHeaderNames, name)
+		redactedValues := make([]string, len(values))
+		for i, v := range values {
+			redactedValues[i] = redacted
+			if !keepScheme {
+				continue
+			}
+			// Keeps the scheme, such as in "Bearer xyz", but not anything
+			// that looks like a parameter, such as in "token=xyz abc".
+			if scheme, _, ok := cutString(v, ' '); ok && !strings.ContainsAny(scheme, "=;,") {
+				redactedValues[i] = scheme + " " + redacted
+			}
+		}
+		clone[name] = redactedValues
+	}
+	return clone
+}
+
+// JSON returns the JSON body with the values of all sensitive fields
+// redacted, at any depth.
+//
+// If the body is not valid JSON, then a pattern-based fallback is used
+// instead, which only redacts sensitive fields with string values.
+func (r *Redactor) JSON(body []byte) []byte {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v interface{}
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return []byte(r.jsonFallback(string(body)))
+	}
+	b, err := json.Marshal(r.jsonValue(v))
+	if err != nil {
+		return []byte(r.jsonFallback(string(body)))
+	}
+	return b
+}
+
+func (r *Redactor) jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if value != nil && value != "" && matchesRedactName(r.JSONFields, key) {
+				v[key] = redacted
+			} else {
+				v[key] = r.jsonValue(value)
+			}
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = r.jsonValue(value)
+		}
+	}
+	return v
+}
+
+// jsonStringFieldPattern matches any JSON object field with a string value.
+var jsonStringFieldPattern = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"\s*:\s*"(?:[^"\\]|\\.)*"`)
+
+// jsonTruncatedFieldPattern matches a JSON object field with a string value
+// that is cut short at the end of the text, such as in a truncated body.
+var jsonTruncatedFieldPattern = regexp.MustCompile(`"((?:[^"\\]|\\.)*)"\s*:\s*"(?:[^"\\]|\\.)*\\?$`)
+
+func (r *Redactor) jsonFallback(body string) string {
+	body = r.replaceJSONFields(jsonStringFieldPattern, body)
+	return r.replaceJSONFields(jsonTruncatedFieldPattern, body)
+}
+
+func (r *Redactor) replaceJSONFields(pattern *regexp.Regexp, body string) string {
+	return pattern.ReplaceAllStringFunc(body, func(match string) string {
+		key := pattern.FindStringSubmatch(match)[1]
+		if !matchesRedactName(r.JSONFields, key) {
+			return match
+		}
+		return `"` + key + `":"` + redacted + `"`
+	})
+}
+
+func matchesRedactName(names []string, name string) bool {
+	normalized := normalizeRedactName(name)
+	for _, n := range names {
+		if normalizeRedactName(n) == normalized {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeRedactName(name string) string {
+	return strings.ToLower(strings.NewReplacer("_", "", "-", "").Replace(name))
+}
@@ -1,26 +1,28 @@
 package wharfapi
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
-	"regexp"
+	"reflect"
+	"strings"
 
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/secret"
 	"github.com/iver-wharf/wharf-core/pkg/logger"
 	"github.com/iver-wharf/wharf-core/pkg/problem"
 )
 
 var redacted = "*REDACTED*"
-var tokenPatternJSON = regexp.MustCompile(`("token"\s*:\s*"([a-zA-Z\d\s]+)")\s*`)
-var tokenReplacementJSON = fmt.Sprintf(`"token":"%s"`, redacted)
 
-func newRequest(method, authHeader, baseURL, path string, q url.Values, body io.Reader) (*http.Request, error) {
+func newRequest(ctx context.Context, method, authHeader, baseURL, path string, q url.Values, body io.Reader) (*http.Request, error) {
 	u, err := newURL(baseURL, path, q)
 	if err != nil {
 		return nil, err
 	}
-	return newRequestFromURL(method, authHeader, u, body)
+	return newRequestFromURL(ctx, method, authHeader, u, body)
 }
 
 func newURL(baseURL, path string, q url.Values) (*url.URL, error) {
@@ -33,9 +35,9 @@ func newURL(baseURL, path string, q url.Values) (*url.URL, error) {
 	return u, nil
 }
 
-func newRequestFromURL(method, authHeader string, u *url.URL, body io.Reader) (*http.Request, error) {
+func newRequestFromURL(ctx context.Context, method, authHeader string, u *url.URL, body io.Reader) (*http.Request, error) {
 	urlStr := u.String()
-	req, err := http.NewRequest(method, urlStr, body)
+	req, err := http.NewRequestWithContext(ctx, method, urlStr, body)
 	if err != nil {
 		log.Error().WithError(err).Message("Failed preparing HTTP request.")
 		return nil, err
@@ -48,17 +50,41 @@ func newRequestFromURL(method, authHeader string, u *url.URL, body io.Reader) (*
 	return req, nil
 }
 
-func doRequest(req *http.Request) (io.ReadCloser, error) {
-	client := &http.Client{}
+func (c *Client) doRequest(req *http.Request) (io.ReadCloser, error) {
+	response, err := c.doRequestResponse(req)
+	if err != nil {
+		return nil, err
+	}
+	return response.Body, nil
+}
+
+// doRequestResponse sends the request and returns an error for any non-2xx
+// response, except for the status codes given in accepted, which are returned
+// as-is for the caller to handle.
+func (c *Client) doRequestResponse(req *http.Request, accepted ...int) (*http.Response, error) {
+	client := c.HTTPClient
+	if client == nil {
+		client = &http.Client{}
+	}
+	var dump *httpDump
+	if c.HTTPDump != nil {
+		dump = c.newHTTPDump(req)
+	}
 	response, err := client.Do(req)
+	if dump != nil {
+		dump.logResponse(response, err)
+	}
 
-	var redactedURL = redactTokenInURL(req.URL.String())
+	redactor := c.redactor()
+	var redactedURL = redactor.URL(req.URL.String())
 	var withRequestMeta = func(ev logger.Event) logger.Event {
 		return ev.
 			WithString("method", req.Method).
 			WithString("url", redactedURL)
 	}
-	log.Debug().WithFunc(withRequestMeta).Message("")
+	log.Debug().WithFunc(withRequestMeta).
+		WithStringer("headers", redactedHeader{req.Header, redactor}).
+		Message("")
 
 	if err != nil {
 		log.Error().WithFunc(withRequestMeta).
@@ -67,7 +93,7 @@ func doRequest(req *http.Request) (io.ReadCloser, error) {
 		return nil, err
 	}
 
-	if isNonSuccessful(response.StatusCode) {
+	if isNonSuccessful(response.StatusCode) && !containsStatusCode(accepted, response.StatusCode) {
 		defer response.Body.Close()
 		if response.StatusCode == http.StatusUnauthorized {
 			log.Error().WithFunc(withRequestMeta).
@@ -92,66 +118,157 @@ func doRequest(req *http.Request) (io.ReadCloser, error) {
 		return nil, fmt.Errorf("unexpected status code returned: %s", response.Status)
 	}
 
-	return response.Body, nil
+	return response, nil
 }
 
-func redactTokenInJSON(src string) string {
-	if !tokenPatternJSON.MatchString(src) {
-		return src
+func (c *Client) redactor() *Redactor {
+	if c.Redactor != nil {
+		return c.Redactor
 	}
+	return &defaultRedactor
+}
 
-	return tokenPatternJSON.ReplaceAllString(src, tokenReplacementJSON)
+// redactedHeader formats HTTP headers with all sensitive values redacted. It
+// implements fmt.Stringer, so it can be passed to logger.Event.WithStringer to
+// only format when logged.
+type redactedHeader struct {
+	header   http.Header
+	redactor *Redactor
 }
 
-func redactTokenInURL(urlStr string) string {
-	if urlStr == "" {
-		return ""
-	}
+func (r redactedHeader) String() string {
+	return fmt.Sprint(r.redactor.Header(r.header))
+}
 
-	uri, err := url.Parse(urlStr)
-	if err != nil {
-		log.Warn().
-			WithError(err).
-			WithString("action", "parse URL").
-			Message("Unable to redact token from URL.")
-		return ""
-	}
+// redactedJSON encodes a value as JSON, with the values of all fields tagged
+// with format:"password", all secret.Secret values, and all fields matched by
+// the redactor, redacted.
+// It implements fmt.Stringer, so it can be passed to
+// logger.Event.WithStringer to only encode when logged.
+type redactedJSON struct {
+	value    interface{}
+	redactor *Redactor
+}
 
-	params, err := url.ParseQuery(uri.RawQuery)
+func (r redactedJSON) String() string {
+	b, err := json.Marshal(redactPasswordFields(reflect.ValueOf(r.value)))
 	if err != nil {
-		log.Warn().
-			WithError(err).
-			WithString("action", "parse query").
-			Message("Unable to redact token from URL.")
-		return ""
+		return fmt.Sprintf("<failed to encode body: %v>", err)
 	}
+	return string(r.redactor.JSON(b))
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	secretType        = reflect.TypeOf(secret.Secret(""))
+)
 
-	token := params.Get("Token")
-	if token != "" {
-		params.Set("Token", redacted)
-	} else {
-		token = params.Get("token")
-		if token != "" {
-			params.Set("token", redacted)
+// redactPasswordFields converts a value into plain maps, slices, and values
+// that encode to the same JSON as the value itself, except with the values of
+// all fields tagged with format:"password", and all secret.Secret values,
+// redacted.
+func redactPasswordFields(v reflect.Value) interface{} {
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
 		}
+		return redactPasswordFields(v.Elem())
 	}
-
-	uri.RawQuery = params.Encode()
-	newURLStr := uri.String()
-
-	sanitized, err := url.PathUnescape(newURLStr)
-	if err != nil {
-		log.Warn().
-			WithString("action", "unescape path").
-			WithError(err).
-			WithString("newURL", newURLStr).
-			Message("Unable to redact token from URL.")
-		return newURLStr
+	if v.Type() == secretType {
+		if v.IsZero() {
+			return ""
+		}
+		return redacted
+	}
+	if v.Type().Implements(jsonMarshalerType) {
+		return v.Interface()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		m := map[string]interface{}{}
+		addRedactedStructFields(m, v)
+		return m
+	case reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return v.Interface()
+		}
+		fallthrough
+	case reflect.Array:
+		s := make([]interface{}, v.Len())
+		for i := range s {
+			s[i] = redactPasswordFields(v.Index(i))
+		}
+		return s
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		m := make(map[string]interface{}, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			m[fmt.Sprint(iter.Key().Interface())] = redactPasswordFields(iter.Value())
+		}
+		return m
+	default:
+		return v.Interface()
 	}
+}
 
-	return sanitized
+func addRedactedStructFields(m map[string]interface{}, v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := cutString(tag, ',')
+		fv := v.Field(i)
+		if field.Anonymous && name == "" {
+			if fv.Kind() == reflect.Ptr {
+				if fv.IsNil() {
+					continue
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct {
+				addRedactedStructFields(m, fv)
+				continue
+			}
+		}
+		if field.PkgPath != "" {
+			// Unexported field.
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		if strings.Contains(opts, "omitempty") && fv.IsZero() {
+			continue
+		}
+		if field.Tag.Get("format") == "password" && !fv.IsZero() {
+			m[name] = redacted
+			continue
+		}
+		m[name] = redactPasswordFields(fv)
+	}
 }
 
 func isNonSuccessful(statusCode int) bool {
 	return statusCode < 200 || statusCode >= 300
 }
+
+func containsStatusCode(statusCodes []int, statusCode int) bool {
+	for _, s := range statusCodes {
+		if s == statusCode {
+			return true
+		}
+	}
+	return false
+}
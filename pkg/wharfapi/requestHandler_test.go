@@ -3,7 +3,11 @@ package wharfapi
 import (
 	"net/http"
 	"testing"
+	"time"
 
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/request"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/secret"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -69,7 +73,7 @@ func TestRemoveTokenFromJSON(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			got := redactTokenInJSON(tc.body)
+			got := defaultRedactor.jsonFallback(tc.body)
 			assert.Equal(t, tc.want, got)
 		})
 	}
@@ -102,12 +106,61 @@ func TestSanitizeURL(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			got := redactTokenInURL(tc.url)
+			got := defaultRedactor.URL(tc.url)
 			assert.Equal(t, tc.want, got)
 		})
 	}
 }
 
+func TestRedactedJSON(t *testing.T) {
+	updatedAt := time.Date(2022, 5, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{
+			name:  "request token",
+			value: request.Token{Token: "hunter2", UserName: "bot"},
+			want:  `{"token":"*REDACTED*","userName":"bot","providerId":0}`,
+		},
+		{
+			name: "embedded struct in paginated list",
+			value: &response.PaginatedTokens{
+				List: []response.Token{{
+					TimeMetadata: response.TimeMetadata{UpdatedAt: &updatedAt},
+					TokenID:      1,
+					Token:        "hunter2",
+				}},
+				TotalCount: 1,
+			},
+			want: `{"list":[{"updatedAt":"2022-05-01T12:00:00Z","createdAt":null,"tokenId":1,"token":"*REDACTED*","userName":""}],"totalCount":1}`,
+		},
+		{
+			name: "secret without password format",
+			value: struct {
+				Key    secret.Secret            `json:"key"`
+				Empty  secret.Secret            `json:"empty"`
+				Nested map[string]secret.Secret `json:"nested"`
+			}{
+				Key:    "hunter2",
+				Nested: map[string]secret.Secret{"a": "hunter2"},
+			},
+			want: `{"key":"*REDACTED*","empty":"","nested":{"a":"*REDACTED*"}}`,
+		},
+		{
+			name:  "empty password is kept empty",
+			value: request.TokenUpdate{UserName: "bot"},
+			want:  `{"token":"","userName":"bot"}`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.JSONEq(t, tc.want, redactedJSON{tc.value, &defaultRedactor}.String())
+		})
+	}
+}
+
 func TestIsNonSuccessful_true(t *testing.T) {
 	tests := []struct {
 		name   string
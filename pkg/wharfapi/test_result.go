@@ -1,27 +1,65 @@
 package wharfapi
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 
+	"github.com/google/go-querystring/query"
 	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/testresult"
 )
 
+// convertedTestResultFileName is the file name used when uploading test
+// results that has been converted to TRX.
+const convertedTestResultFileName = "tests.trx"
+
+// TestResultDetailSearch is used when filtering test result details through
+// the HTTP requests:
+//  GET /api/build/{buildId}/test-result/detail
+//  GET /api/build/{buildId}/test-result/summary/{artifactId}/detail
+type TestResultDetailSearch struct {
+	Limit     *int     `url:"limit,omitempty"`
+	Offset    *int     `url:"offset,omitempty"`
+	OrderBy   []string `url:"orderby,omitempty"`
+	Status    []string `url:"status,omitempty"`
+	Name      *string  `url:"name,omitempty"`
+	NameMatch *string  `url:"nameMatch,omitempty"`
+}
+
 // GetBuildAllTestResultDetailList fetches all the test result
 // details for the specified build by invoking the HTTP request:
 //  GET /api/build/{buildId}/test-result/detail
 //
 // Added in wharf-api v5.0.0.
 func (c *Client) GetBuildAllTestResultDetailList(buildID uint) (response.PaginatedTestResultDetails, error) {
-	if err := c.validateEndpointVersion(5, 0, 0); err != nil {
-		return response.PaginatedTestResultDetails{}, err
-	}
-	var details response.PaginatedTestResultDetails
+	return c.SearchBuildAllTestResultDetailList(TestResultDetailSearch{}, buildID)
+}
+
+// SearchBuildAllTestResultDetailList filters the test result details for the
+// specified build based on the parameters by invoking the HTTP request:
+//  GET /api/build/{buildId}/test-result/detail
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) SearchBuildAllTestResultDetailList(params TestResultDetailSearch, buildID uint) (response.PaginatedTestResultDetails, error) {
 	path := fmt.Sprintf("/api/build/%d/test-result/detail", buildID)
-	err := c.getUnmarshal(path, nil, &details)
-	return details, err
+	return c.searchTestResultDetailList(path, params)
+}
+
+// IterateBuildAllTestResultDetails returns an iterator over the test result
+// details for the specified build that match the parameters, by invoking the
+// HTTP request once per page:
+//  GET /api/build/{buildId}/test-result/detail
+//
+// The TestResultDetailSearch.Limit field is used as page size, and defaults
+// to 100.
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) IterateBuildAllTestResultDetails(params TestResultDetailSearch, buildID uint) *TestResultDetailIterator {
+	path := fmt.Sprintf("/api/build/%d/test-result/detail", buildID)
+	return c.newTestResultDetailIterator(path, params)
 }
 
 // GetBuildAllTestResultSummaryList fetches all the test result
@@ -60,15 +98,121 @@ func (c *Client) GetBuildTestResultSummary(buildID, artifactID uint) (response.T
 //
 // Added in wharf-api v5.0.0.
 func (c *Client) GetBuildTestResultDetailList(buildID, artifactID uint) (response.PaginatedTestResultDetails, error) {
+	return c.SearchBuildTestResultDetailList(TestResultDetailSearch{}, buildID, artifactID)
+}
+
+// SearchBuildTestResultDetailList filters the test result details for the
+// specified test result summary based on the parameters by invoking the HTTP
+// request:
+//  GET /api/build/{buildId}/test-result/summary/{artifactId}/detail
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) SearchBuildTestResultDetailList(params TestResultDetailSearch, buildID, artifactID uint) (response.PaginatedTestResultDetails, error) {
+	path := fmt.Sprintf("/api/build/%d/test-result/summary/%d/detail", buildID, artifactID)
+	return c.searchTestResultDetailList(path, params)
+}
+
+// IterateBuildTestResultDetails returns an iterator over the test result
+// details for the specified test result summary that match the parameters, by
+// invoking the HTTP request once per page:
+//  GET /api/build/{buildId}/test-result/summary/{artifactId}/detail
+//
+// The TestResultDetailSearch.Limit field is used as page size, and defaults
+// to 100.
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) IterateBuildTestResultDetails(params TestResultDetailSearch, buildID, artifactID uint) *TestResultDetailIterator {
+	path := fmt.Sprintf("/api/build/%d/test-result/summary/%d/detail", buildID, artifactID)
+	return c.newTestResultDetailIterator(path, params)
+}
+
+func (c *Client) searchTestResultDetailList(path string, params TestResultDetailSearch) (response.PaginatedTestResultDetails, error) {
 	if err := c.validateEndpointVersion(5, 0, 0); err != nil {
 		return response.PaginatedTestResultDetails{}, err
 	}
 	var details response.PaginatedTestResultDetails
-	path := fmt.Sprintf("/api/build/%d/test-result/summary/%d/detail", buildID, artifactID)
-	err := c.getUnmarshal(path, nil, &details)
+	q, err := query.Values(params)
+	if err != nil {
+		return details, err
+	}
+	err = c.getUnmarshal(path, q, &details)
 	return details, err
 }
 
+func (c *Client) newTestResultDetailIterator(path string, params TestResultDetailSearch) *TestResultDetailIterator {
+	it := &TestResultDetailIterator{pageSize: defaultPageSize}
+	if params.Limit != nil && *params.Limit > 0 {
+		it.pageSize = *params.Limit
+	}
+	if params.Offset != nil && *params.Offset > 0 {
+		it.offset = *params.Offset
+	}
+	it.fetch = func(limit, offset int) (response.PaginatedTestResultDetails, error) {
+		params.Limit = &limit
+		params.Offset = &offset
+		return c.searchTestResultDetailList(path, params)
+	}
+	return it
+}
+
+// TestResultDetailIterator iterates over test result details, fetching one
+// page at a time. Use it in the same way as a bufio.Scanner:
+//  it := c.IterateBuildAllTestResultDetails(params, buildID)
+//  for it.Next() {
+//  	detail := it.Detail()
+//  }
+//  if err := it.Err(); err != nil {
+//  	// handle error
+//  }
+type TestResultDetailIterator struct {
+	fetch    func(limit, offset int) (response.PaginatedTestResultDetails, error)
+	pageSize int
+	offset   int
+	page     []response.TestResultDetail
+	index    int
+	done     bool
+	detail   response.TestResultDetail
+	err      error
+}
+
+// Next advances the iterator to the next test result detail, fetching the
+// next page if needed. It returns false when there are no more details, or if
+// fetching a page failed, in which case Err returns the error.
+func (it *TestResultDetailIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	for it.index >= len(it.page) {
+		if it.done {
+			return false
+		}
+		page, err := it.fetch(it.pageSize, it.offset)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page = page.List
+		it.index = 0
+		it.offset += len(page.List)
+		if len(page.List) == 0 || int64(it.offset) >= page.TotalCount {
+			it.done = true
+		}
+	}
+	it.detail = it.page[it.index]
+	it.index++
+	return true
+}
+
+// Detail returns the current test result detail, as advanced to by Next.
+func (it *TestResultDetailIterator) Detail() response.TestResultDetail {
+	return it.detail
+}
+
+// Err returns the first error that occurred while fetching pages.
+func (it *TestResultDetailIterator) Err() error {
+	return it.err
+}
+
 // GetBuildAllTestResultListSummary fetches the test result list summary of all tests for
 // the specified build.
 //  GET /api/build/{buildId}/test-result/list-summary
@@ -79,11 +223,39 @@ func (c *Client) GetBuildAllTestResultListSummary(buildID uint) (response.TestRe
 		return response.TestResultListSummary{}, err
 	}
 	var listSummary response.TestResultListSummary
-	path := fmt.Sprintf("/api/build%d/test-result/list-summary", buildID)
+	path := fmt.Sprintf("/api/build/%d/test-result/list-summary", buildID)
 	err := c.getUnmarshal(path, nil, &listSummary)
 	return listSummary, err
 }
 
+// GetBuildTestResultReport fetches all test results of the specified build,
+// which can be rendered as Markdown, HTML, or JUnit XML, by invoking the HTTP
+// requests:
+//  GET /api/build/{buildId}/test-result/list-summary
+//  GET /api/build/{buildId}/test-result/summary
+//  GET /api/build/{buildId}/test-result/detail
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) GetBuildTestResultReport(buildID uint) (testresult.Report, error) {
+	report := testresult.Report{BuildID: buildID}
+	listSummary, err := c.GetBuildAllTestResultListSummary(buildID)
+	if err != nil {
+		return report, fmt.Errorf("get test result list summary: %w", err)
+	}
+	summaries, err := c.GetBuildAllTestResultSummaryList(buildID)
+	if err != nil {
+		return report, fmt.Errorf("get test result summaries: %w", err)
+	}
+	details, err := c.GetBuildAllTestResultDetailList(buildID)
+	if err != nil {
+		return report, fmt.Errorf("get test result details: %w", err)
+	}
+	report.ListSummary = listSummary
+	report.Summaries = summaries.List
+	report.Details = details.List
+	return report, nil
+}
+
 // CreateBuildTestResult uploads a test result file (eg: "tests.trx") by
 // invoking the HTTP request:
 //  POST /api/build/{buildId}/test-result
@@ -94,12 +266,9 @@ func (c *Client) CreateBuildTestResult(buildID uint, fileName string, testResult
 		return nil, err
 	}
 	path := fmt.Sprintf("/api/build/%d/test-result/", buildID)
-	body, err := c.uploadMultipart(http.MethodPost, path, map[string]file{
-		"files": {
-			fileName: fileName,
-			reader:   testResult,
-		},
-	})
+	body, err := c.uploadMultipart(context.Background(), http.MethodPost, path, []file{
+		newReaderFile("files", fileName, 0, testResult),
+	}, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -110,3 +279,165 @@ func (c *Client) CreateBuildTestResult(buildID uint, fileName string, testResult
 	}
 	return resp, nil
 }
+
+// CreateBuildTestResultFrom converts test results of the given format to TRX
+// and uploads them in a single step by invoking the HTTP request:
+//  POST /api/build/{buildId}/test-result
+//
+// Test results in other formats than TRX are parsed in full before the upload
+// starts, so malformed test results are reported before anything is sent. The
+// converted TRX file is then streamed in the request body without being
+// buffered. Test results that already are in the TRX format are uploaded
+// as-is.
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) CreateBuildTestResultFrom(ctx context.Context, buildID uint, format testresult.Format, testResult io.Reader) ([]response.ArtifactMetadata, error) {
+	if err := c.validateEndpointVersion(5, 0, 0); err != nil {
+		return nil, err
+	}
+	var f file
+	if format == testresult.FormatTRX {
+		f = newReaderFile("files", convertedTestResultFileName, 0, testResult)
+	} else {
+		res, err := testresult.Parse(format, testResult)
+		if err != nil {
+			return nil, fmt.Errorf("parse test results: %w", err)
+		}
+		f = file{
+			fieldName: "files",
+			fileName:  convertedTestResultFileName,
+			open: func() (io.ReadCloser, error) {
+				r, w := io.Pipe()
+				go func() {
+					w.CloseWithError(testresult.WriteTRX(w, res))
+				}()
+				return r, nil
+			},
+		}
+	}
+	path := fmt.Sprintf("/api/build/%d/test-result/", buildID)
+	body, err := c.uploadMultipart(ctx, http.MethodPost, path, []file{f}, nil)
+	if err != nil {
+		return nil, err
+	}
+	var resp []response.ArtifactMetadata
+	err = c.decodeResponse(body, &resp)
+	return resp, err
+}
+
+// CompareBuildTestResults fetches the test result details of two builds and
+// compares them, by invoking the HTTP requests:
+//  GET /api/build/{baseBuildId}/test-result/detail
+//  GET /api/build/{headBuildId}/test-result/detail
+//
+// Tests are matched by name. See testresult.Compare for details.
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) CompareBuildTestResults(baseBuildID, headBuildID uint, opts testresult.CompareOptions) (testresult.Diff, error) {
+	base, err := c.GetBuildAllTestResultDetailList(baseBuildID)
+	if err != nil {
+		return testresult.Diff{}, fmt.Errorf("get test results of base build %d: %w", baseBuildID, err)
+	}
+	head, err := c.GetBuildAllTestResultDetailList(headBuildID)
+	if err != nil {
+		return testresult.Diff{}, fmt.Errorf("get test results of head build %d: %w", headBuildID, err)
+	}
+	diff := testresult.Compare(base.List, head.List, opts)
+	diff.BaseBuildID = baseBuildID
+	diff.HeadBuildID = headBuildID
+	return diff, nil
+}
+
+// defaultFlakyTestBuildCount is the default number of builds analyzed when
+// searching for flaky tests.
+const defaultFlakyTestBuildCount = 20
+
+// FlakyTestSearch is used when searching for flaky tests among a project's
+// recent builds.
+type FlakyTestSearch struct {
+	// ProjectID is the project to analyze builds from. Required.
+	ProjectID uint
+	// GitBranch, if set, only analyzes builds from the given branch. If not
+	// set, builds from all branches are analyzed, but test statuses are only
+	// compared between builds of the same branch, as different branches are
+	// expected to have different test outcomes.
+	GitBranch string
+	// Builds is the number of most recent finished builds to analyze.
+	// Defaults to 20.
+	Builds int
+	// MinFlips is the minimum number of times a test must change between
+	// Success and Failed to be considered flaky. Defaults to 2.
+	MinFlips int
+}
+
+// FlakyTestReport is the result of a flaky test search.
+type FlakyTestReport struct {
+	ProjectID uint
+	GitBranch string
+	// BuildIDs is the list of analyzed builds, newest first.
+	BuildIDs []uint
+	Tests    []testresult.FlakyTest
+}
+
+// FindFlakyTests walks a project's most recent finished builds and flags tests
+// whose status flips between Success and Failed, by invoking the HTTP
+// requests:
+//  GET /api/build?projectId={projectId}&gitBranch={gitBranch}
+//  GET /api/build/{buildId}/test-result/detail
+//
+// See testresult.DetectFlaky for details.
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) FindFlakyTests(params FlakyTestSearch) (FlakyTestReport, error) {
+	report := FlakyTestReport{
+		ProjectID: params.ProjectID,
+		GitBranch: params.GitBranch,
+	}
+	limit := params.Builds
+	if limit <= 0 {
+		limit = defaultFlakyTestBuildCount
+	}
+	search := BuildSearch{
+		Limit:     &limit,
+		OrderBy:   []string{response.BuildJSONFields.BuildID + " desc"},
+		ProjectID: &params.ProjectID,
+		Status: []string{
+			string(response.BuildCompleted),
+			string(response.BuildFailed),
+		},
+	}
+	if params.GitBranch != "" {
+		search.GitBranch = &params.GitBranch
+	}
+	builds, err := c.GetBuildList(search)
+	if err != nil {
+		return report, fmt.Errorf("get build list: %w", err)
+	}
+	buildDetails := make([]testresult.BuildDetails, 0, len(builds.List))
+	for _, build := range builds.List {
+		// Skipped tests are ignored when detecting flaky tests anyway.
+		it := c.IterateBuildAllTestResultDetails(TestResultDetailSearch{
+			Status: []string{
+				string(response.TestResultStatusSuccess),
+				string(response.TestResultStatusFailed),
+			},
+		}, build.BuildID)
+		var details []response.TestResultDetail
+		for it.Next() {
+			details = append(details, it.Detail())
+		}
+		if err := it.Err(); err != nil {
+			return report, fmt.Errorf("get test results of build %d: %w", build.BuildID, err)
+		}
+		report.BuildIDs = append(report.BuildIDs, build.BuildID)
+		buildDetails = append(buildDetails, testresult.BuildDetails{
+			BuildID:   build.BuildID,
+			GitBranch: build.GitBranch,
+			Details:   details,
+		})
+	}
+	report.Tests = testresult.DetectFlaky(buildDetails, testresult.FlakyOptions{
+		MinFlips: params.MinFlips,
+	})
+	return report, nil
+}
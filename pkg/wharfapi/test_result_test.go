@@ -0,0 +1,134 @@
+package wharfapi
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/testresult"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateBuildTestResultFrom(t *testing.T) {
+	var uploaded []testUploadedFile
+	srv := newTestUploadServer(t, &uploaded)
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+
+	input := `{"Action":"pass","Package":"p","Test":"TestFoo"}`
+	_, err := c.CreateBuildTestResultFrom(context.Background(), 1, testresult.FormatGoTestJSON, strings.NewReader(input))
+	require.NoError(t, err)
+	require.Len(t, uploaded, 1)
+	assert.Equal(t, "tests.trx", uploaded[0].fileName)
+
+	res, err := testresult.ParseTRX(strings.NewReader(uploaded[0].content))
+	require.NoError(t, err)
+	require.Len(t, res.Details, 1)
+	assert.Equal(t, "p.TestFoo", res.Details[0].Name)
+}
+
+func TestCreateBuildTestResultFrom_invalidInput(t *testing.T) {
+	c := Client{APIURL: "http://localhost:0", DisableOutdatedLogging: true}
+	_, err := c.CreateBuildTestResultFrom(context.Background(), 1, testresult.FormatJUnit, io.MultiReader())
+	assert.Error(t, err)
+}
+
+func TestGetBuildTestResultReport(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/build/7/test-result/list-summary", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"buildId":7,"total":2,"failed":1,"passed":1}`)
+	})
+	mux.HandleFunc("/api/build/7/test-result/summary", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"list":[{"fileName":"tests.trx","artifactId":3,"buildId":7,"total":2,"failed":1,"passed":1}],"totalCount":1}`)
+	})
+	mux.HandleFunc("/api/build/7/test-result/detail", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, `{"list":[
+			{"artifactId":3,"buildId":7,"name":"TestA","status":"Success"},
+			{"artifactId":3,"buildId":7,"name":"TestB","status":"Failed","message":"boom"}
+		],"totalCount":2}`)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+
+	report, err := c.GetBuildTestResultReport(7)
+	require.NoError(t, err)
+	assert.Equal(t, uint(2), report.ListSummary.Total)
+	files := report.Files()
+	require.Len(t, files, 1)
+	assert.Equal(t, "tests.trx", files[0].Summary.FileName)
+	assert.Len(t, files[0].Details, 2)
+}
+
+func TestIterateBuildAllTestResultDetails(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/build/7/test-result/detail", r.URL.Path)
+		queries = append(queries, r.URL.RawQuery)
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		var list []response.TestResultDetail
+		for i := offset; i < offset+2 && i < 5; i++ {
+			list = append(list, response.TestResultDetail{Name: "Test" + strconv.Itoa(i)})
+		}
+		json.NewEncoder(w).Encode(response.PaginatedTestResultDetails{List: list, TotalCount: 5})
+	}))
+	defer srv.Close()
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+
+	limit := 2
+	it := c.IterateBuildAllTestResultDetails(TestResultDetailSearch{
+		Limit:  &limit,
+		Status: []string{"Failed"},
+	}, 7)
+	var names []string
+	for it.Next() {
+		names = append(names, it.Detail().Name)
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, []string{"Test0", "Test1", "Test2", "Test3", "Test4"}, names)
+	assert.Equal(t, []string{
+		"limit=2&offset=0&status=Failed",
+		"limit=2&offset=2&status=Failed",
+		"limit=2&offset=4&status=Failed",
+	}, queries)
+}
+
+func TestFindFlakyTests_multipleBranches(t *testing.T) {
+	builds := []response.Build{
+		{BuildID: 6, GitBranch: "feature"},
+		{BuildID: 5, GitBranch: "master"},
+		{BuildID: 4, GitBranch: "feature"},
+		{BuildID: 3, GitBranch: "master"},
+		{BuildID: 2, GitBranch: "feature"},
+		{BuildID: 1, GitBranch: "master"},
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/build", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(response.PaginatedBuilds{List: builds, TotalCount: int64(len(builds))})
+	})
+	mux.HandleFunc("/api/build/", func(w http.ResponseWriter, r *http.Request) {
+		// Always fails on the feature branch and always passes on master.
+		status := response.TestResultStatusSuccess
+		if id := strings.Split(r.URL.Path, "/")[3]; id == "2" || id == "4" || id == "6" {
+			status = response.TestResultStatusFailed
+		}
+		json.NewEncoder(w).Encode(response.PaginatedTestResultDetails{
+			List:       []response.TestResultDetail{{Name: "TestFoo", Status: status}},
+			TotalCount: 1,
+		})
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+
+	report, err := c.FindFlakyTests(FlakyTestSearch{ProjectID: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []uint{6, 5, 4, 3, 2, 1}, report.BuildIDs)
+	assert.Empty(t, report.Tests)
+}
@@ -1,6 +1,7 @@
 package wharfapi
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/google/go-querystring/query"
@@ -52,6 +53,37 @@ func (c *Client) GetTokenList(params TokenSearch) (response.PaginatedTokens, err
 	return tokens, err
 }
 
+// GetTokenListAll fetches all tokens matching the parameters by repeatedly
+// invoking the HTTP request:
+//  GET /api/token
+//
+// The TokenSearch.Limit field is used as page size, and defaults to 100.
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) GetTokenListAll(params TokenSearch) ([]response.Token, error) {
+	return c.listTokens(context.Background(), params)
+}
+
+// listTokens fetches all tokens matching the parameters, one page at a
+// time. Used by GetTokenListAll and the context-aware methods.
+func (c *Client) listTokens(ctx context.Context, params TokenSearch) ([]response.Token, error) {
+	if err := c.validateEndpointVersion(5, 0, 0); err != nil {
+		return nil, err
+	}
+	var tokens []response.Token
+	err := fetchAllPages(params.Limit, params.Offset, func(limit, offset int) (int, int64, error) {
+		params.Limit = &limit
+		params.Offset = &offset
+		var page response.PaginatedTokens
+		if err := c.listContext(ctx, "/api/token", params, &page); err != nil {
+			return 0, 0, err
+		}
+		tokens = append(tokens, page.List...)
+		return len(page.List), page.TotalCount, nil
+	})
+	return tokens, err
+}
+
 // UpdateToken updates the token with the specified ID by invoking the HTTP request:
 //  PUT /api/token/{tokenID}
 //
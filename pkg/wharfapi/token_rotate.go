@@ -0,0 +1,192 @@
+package wharfapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/request"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/secret"
+)
+
+// ErrMissingNewToken is returned when rotating a token without a new token
+// value.
+var ErrMissingNewToken = errors.New("missing new token value")
+
+// TokenRotationOptions specifies how a token is rotated.
+type TokenRotationOptions struct {
+	// NewToken is the new token value. Required.
+	NewToken secret.Secret
+	// Repoint creates a new token, with the same user name as the old
+	// token, and re-points all providers and projects using the old token to
+	// the new token. The old token is left as-is, so it can be deleted once
+	// the rotation is verified.
+	//
+	// If false, the old token's value is updated in place instead, and the
+	// providers and projects are left untouched.
+	Repoint bool
+	// DryRun only enumerates the dependent providers and projects, without
+	// making any changes.
+	DryRun bool
+}
+
+// TokenRotationReport is the result of rotating a token.
+type TokenRotationReport struct {
+	DryRun     bool
+	OldTokenID uint
+	// NewTokenID is the ID of the token now in use. It is the same as
+	// OldTokenID when the token was updated in place, and zero when doing a
+	// dry run of a re-pointing rotation, as the new token is not yet created.
+	NewTokenID uint
+	// Providers and Projects are the records that used the old token, as
+	// they were before the rotation.
+	Providers []response.Provider
+	Projects  []response.Project
+	// RepointedProviderIDs and RepointedProjectIDs are the IDs of the records
+	// that were re-pointed to the new token.
+	RepointedProviderIDs []uint
+	RepointedProjectIDs  []uint
+}
+
+// WriteText writes a human-readable summary of the rotation.
+func (r TokenRotationReport) WriteText(w io.Writer) error {
+	var prefix string
+	if r.DryRun {
+		prefix = "(dry run) "
+	}
+	var err error
+	printf := func(format string, args ...interface{}) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+	switch {
+	case r.NewTokenID == r.OldTokenID:
+		printf("%sToken %d updated in place.\n", prefix, r.OldTokenID)
+	case r.NewTokenID == 0:
+		printf("%sToken %d to be replaced by a new token.\n", prefix, r.OldTokenID)
+	default:
+		printf("%sToken %d replaced by token %d.\n", prefix, r.OldTokenID, r.NewTokenID)
+	}
+	for _, p := range r.Providers {
+		printf("  provider %d: %s %s\n", p.ProviderID, p.Name, p.URL)
+	}
+	for _, p := range r.Projects {
+		printf("  project %d: %s/%s\n", p.ProjectID, p.GroupName, p.Name)
+	}
+	printf("%d providers and %d projects use the token, %d providers and %d projects re-pointed.\n",
+		len(r.Providers), len(r.Projects),
+		len(r.RepointedProviderIDs), len(r.RepointedProjectIDs))
+	return err
+}
+
+// RotateToken replaces a token's value, and reports which providers and
+// projects depend on it, by invoking the HTTP requests:
+//  GET /api/token/{tokenId}
+//  GET /api/provider
+//  GET /api/project?tokenId={tokenId}
+//  PUT /api/token/{tokenId}
+//
+// Or, if the Repoint option is set, the HTTP requests:
+//  GET /api/token/{tokenId}
+//  GET /api/provider
+//  GET /api/project?tokenId={tokenId}
+//  POST /api/token
+//  PUT /api/provider/{providerId}
+//  PUT /api/project/{projectId}
+//
+// As the provider list endpoint cannot filter by token ID, all providers are
+// fetched and filtered client-side.
+//
+// If re-pointing fails midway, the partial report is returned together with
+// the error, so the remaining records can be handled manually.
+//
+// Added in wharf-api v5.0.0.
+func (c *Client) RotateToken(ctx context.Context, tokenID uint, opts TokenRotationOptions) (TokenRotationReport, error) {
+	report := TokenRotationReport{DryRun: opts.DryRun, OldTokenID: tokenID}
+	if opts.NewToken == "" {
+		return report, fmt.Errorf("rotate token %d: %w", tokenID, ErrMissingNewToken)
+	}
+	if err := c.validateEndpointVersion(5, 0, 0); err != nil {
+		return report, err
+	}
+	var old response.Token
+	if err := c.getUnmarshalContext(ctx, fmt.Sprintf("/api/token/%d", tokenID), nil, &old); err != nil {
+		return report, fmt.Errorf("rotate token %d: %w", tokenID, err)
+	}
+	if err := c.findTokenDependents(ctx, &report); err != nil {
+		return report, fmt.Errorf("rotate token %d: %w", tokenID, err)
+	}
+
+	if !opts.Repoint {
+		report.NewTokenID = tokenID
+		if opts.DryRun {
+			return report, nil
+		}
+		_, err := c.PatchToken(ctx, tokenID, func(t *request.TokenUpdate) {
+			t.Token = opts.NewToken
+		})
+		if err != nil {
+			return report, fmt.Errorf("rotate token %d: %w", tokenID, err)
+		}
+		return report, nil
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+	var newToken response.Token
+	err := c.postJSONUnmarshalContext(ctx, "/api/token", nil, request.Token{
+		Token:    opts.NewToken,
+		UserName: old.UserName,
+	}, &newToken)
+	if err != nil {
+		return report, fmt.Errorf("rotate token %d: create new token: %w", tokenID, err)
+	}
+	report.NewTokenID = newToken.TokenID
+	for _, p := range report.Providers {
+		_, err := c.PatchProvider(ctx, p.ProviderID, func(u *request.ProviderUpdate) {
+			u.TokenID = newToken.TokenID
+		})
+		if err != nil {
+			return report, fmt.Errorf("rotate token %d: %w", tokenID, err)
+		}
+		report.RepointedProviderIDs = append(report.RepointedProviderIDs, p.ProviderID)
+	}
+	for _, p := range report.Projects {
+		_, err := c.PatchProject(ctx, p.ProjectID, func(u *request.ProjectUpdate) {
+			u.TokenID = newToken.TokenID
+		})
+		if err != nil {
+			return report, fmt.Errorf("rotate token %d: %w", tokenID, err)
+		}
+		report.RepointedProjectIDs = append(report.RepointedProjectIDs, p.ProjectID)
+	}
+	return report, nil
+}
+
+func (c *Client) findTokenDependents(ctx context.Context, report *TokenRotationReport) error {
+	providers, err := c.listProviders(ctx, ProviderSearch{})
+	if err != nil {
+		return fmt.Errorf("list providers: %w", err)
+	}
+	for _, p := range providers {
+		if p.TokenID == report.OldTokenID {
+			report.Providers = append(report.Providers, p)
+		}
+	}
+	tokenID := report.OldTokenID
+	projects, err := c.listProjects(ctx, ProjectSearch{TokenID: &tokenID})
+	if err != nil {
+		return fmt.Errorf("list projects: %w", err)
+	}
+	for _, p := range projects {
+		// Filtered again in case the server ignores the filter.
+		if p.TokenID == report.OldTokenID {
+			report.Projects = append(report.Projects, p)
+		}
+	}
+	return nil
+}
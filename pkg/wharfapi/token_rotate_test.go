@@ -0,0 +1,138 @@
+package wharfapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTokenRotationTestServer(t *testing.T, writes *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		route := r.Method + " " + r.URL.Path
+		switch route {
+		case "GET /api/token/3":
+			fmt.Fprint(w, `{"tokenId":3,"token":"old","userName":"bot"}`)
+		case "GET /api/provider":
+			fmt.Fprint(w, `{"totalCount":2,"list":[
+				{"providerId":1,"name":"gitlab","url":"https://gitlab.com","tokenId":3},
+				{"providerId":2,"name":"github","url":"https://github.com","tokenId":4}]}`)
+		case "GET /api/project":
+			assert.Equal(t, "3", r.URL.Query().Get("tokenId"))
+			fmt.Fprint(w, `{"totalCount":1,"list":[{"projectId":5,"groupName":"team","name":"api","tokenId":3}]}`)
+		case "GET /api/provider/1":
+			fmt.Fprint(w, `{"providerId":1,"name":"gitlab","url":"https://gitlab.com","tokenId":3}`)
+		case "GET /api/project/5":
+			fmt.Fprint(w, `{"projectId":5,"groupName":"team","name":"api","tokenId":3}`)
+		case "POST /api/token":
+			var body map[string]interface{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "bot", body["userName"])
+			*writes = append(*writes, route)
+			fmt.Fprint(w, `{"tokenId":9,"token":"new","userName":"bot"}`)
+		case "PUT /api/token/3":
+			var body map[string]interface{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, map[string]interface{}{"token": "new", "userName": "bot"}, body)
+			*writes = append(*writes, route)
+			fmt.Fprint(w, `{"tokenId":3,"token":"new","userName":"bot"}`)
+		case "PUT /api/provider/1", "PUT /api/project/5":
+			var body map[string]interface{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, float64(9), body["tokenId"])
+			*writes = append(*writes, route)
+			fmt.Fprint(w, `{}`)
+		default:
+			t.Errorf("unexpected request: %s", route)
+		}
+	}))
+}
+
+func TestRotateToken_repoint(t *testing.T) {
+	var writes []string
+	srv := newTokenRotationTestServer(t, &writes)
+	defer srv.Close()
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+
+	report, err := c.RotateToken(context.Background(), 3, TokenRotationOptions{
+		NewToken: "new",
+		Repoint:  true,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint(9), report.NewTokenID)
+	assert.Equal(t, []uint{1}, report.RepointedProviderIDs)
+	assert.Equal(t, []uint{5}, report.RepointedProjectIDs)
+	assert.Equal(t, []string{"POST /api/token", "PUT /api/provider/1", "PUT /api/project/5"}, writes)
+}
+
+func TestRotateToken_inPlace(t *testing.T) {
+	var writes []string
+	srv := newTokenRotationTestServer(t, &writes)
+	defer srv.Close()
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+
+	report, err := c.RotateToken(context.Background(), 3, TokenRotationOptions{
+		NewToken: "new",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), report.NewTokenID)
+	assert.Empty(t, report.RepointedProviderIDs)
+	assert.Empty(t, report.RepointedProjectIDs)
+	assert.Equal(t, []string{"PUT /api/token/3"}, writes)
+}
+
+func TestRotateToken_inPlaceError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.Path {
+		case "GET /api/token/3":
+			fmt.Fprint(w, `{"tokenId":3,"token":"old","userName":"bot"}`)
+		case "GET /api/provider", "GET /api/project":
+			fmt.Fprint(w, `{"totalCount":0,"list":[]}`)
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+
+	_, err := c.RotateToken(context.Background(), 3, TokenRotationOptions{
+		NewToken: "new",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "rotate token 3: ")
+}
+
+func TestRotateToken_dryRun(t *testing.T) {
+	var writes []string
+	srv := newTokenRotationTestServer(t, &writes)
+	defer srv.Close()
+	c := Client{APIURL: srv.URL, DisableOutdatedLogging: true}
+
+	report, err := c.RotateToken(context.Background(), 3, TokenRotationOptions{
+		NewToken: "new",
+		Repoint:  true,
+		DryRun:   true,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, writes)
+	require.Len(t, report.Providers, 1)
+	require.Len(t, report.Projects, 1)
+
+	var buf bytes.Buffer
+	require.NoError(t, report.WriteText(&buf))
+	assert.Contains(t, buf.String(), "(dry run) Token 3 to be replaced by a new token.")
+	assert.Contains(t, buf.String(), "project 5: team/api")
+}
+
+func TestRotateToken_missingNewToken(t *testing.T) {
+	c := Client{DisableOutdatedLogging: true}
+	_, err := c.RotateToken(context.Background(), 3, TokenRotationOptions{})
+	assert.True(t, errors.Is(err, ErrMissingNewToken))
+}
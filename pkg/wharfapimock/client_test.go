@@ -0,0 +1,32 @@
+package wharfapimock
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/wharfapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func projectName(projects wharfapi.Projects, projectID uint) (string, error) {
+	project, err := projects.GetProject(projectID)
+	if err != nil {
+		return "", err
+	}
+	return project.GroupName + "/" + project.Name, nil
+}
+
+func TestClient(t *testing.T) {
+	m := NewClient(t)
+	m.On("GetProject", uint(5)).Return(response.Project{GroupName: "team", Name: "api"}, nil).Once()
+	m.On("GetProject", uint(6)).Return(response.Project{}, errors.New("not found")).Once()
+
+	name, err := projectName(m, 5)
+	require.NoError(t, err)
+	assert.Equal(t, "team/api", name)
+
+	_, err = projectName(m, 6)
+	assert.EqualError(t, err, "not found")
+}
@@ -0,0 +1,12 @@
+// Package wharfapimock contains a mock of the wharfapi.API interface, for use
+// with the github.com/stretchr/testify/mock package in unit tests of code that
+// depends on any of the wharfapi interfaces, such as wharfapi.Projects.
+//
+// The mock is generated using mockery. Regenerate it after changing the
+// wharfapi interfaces by running:
+//  make mock
+package wharfapimock
+
+import "github.com/iver-wharf/wharf-api-client-go/v2/pkg/wharfapi"
+
+var _ wharfapi.API = (*Client)(nil)
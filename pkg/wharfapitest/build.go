@@ -0,0 +1,485 @@
+package wharfapitest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/request"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/testresult"
+	"gopkg.in/guregu/null.v4"
+)
+
+// maxMultipartMemory is the number of bytes of uploaded files kept in memory
+// while parsing, where the rest is stored in temporary files.
+const maxMultipartMemory = 32 << 20
+
+var buildStatusIDs = map[response.BuildStatus]int{
+	response.BuildScheduling: 0,
+	response.BuildRunning:    1,
+	response.BuildCompleted:  2,
+	response.BuildFailed:     3,
+}
+
+// AddBuild adds a build directly, such as to set up builds with a history
+// that cannot be created using the endpoints. The BuildID is set to a new ID,
+// and the stored build is returned.
+func (s *Server) AddBuild(build response.Build) response.Build {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	build.BuildID = s.newID()
+	if build.Status == "" {
+		build.Status = response.BuildScheduling
+	}
+	build.StatusID = buildStatusIDs[build.Status]
+	if build.CreatedAt == nil {
+		build.TimeMetadata = newTimeMetadata()
+	}
+	s.builds[build.BuildID] = build
+	return build
+}
+
+func (s *Server) deleteBuild(buildID uint) {
+	delete(s.builds, buildID)
+	delete(s.logs, buildID)
+	for id, a := range s.artifacts {
+		if a.BuildID == buildID {
+			delete(s.artifacts, id)
+			delete(s.summaries, id)
+			delete(s.testDetails, id)
+		}
+	}
+}
+
+func (s *Server) startBuild(w http.ResponseWriter, r *http.Request, ids []uint) {
+	if _, ok := s.projects[ids[0]]; !ok {
+		writeNotFound(w, r, "project", ids[0])
+		return
+	}
+	q := r.URL.Query()
+	var inputs request.BuildInputs
+	if r.ContentLength != 0 && !readJSON(w, r, &inputs) {
+		return
+	}
+	engine, ok := s.resolveEngine(q.Get("engine"))
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, ProblemTypeInvalidParam,
+			"Unknown engine: "+strconv.Quote(q.Get("engine")))
+		return
+	}
+	branch := q.Get("branch")
+	if branch == "" {
+		for _, b := range s.branches[ids[0]] {
+			if b.Default {
+				branch = b.Name
+			}
+		}
+	}
+	build := response.Build{
+		TimeMetadata: newTimeMetadata(),
+		BuildID:      s.newID(),
+		StatusID:     buildStatusIDs[response.BuildScheduling],
+		Status:       response.BuildScheduling,
+		ProjectID:    ids[0],
+		ScheduledOn:  null.TimeFrom(time.Now().UTC()),
+		GitBranch:    branch,
+		Stage:        q.Get("stage"),
+		Params:       []response.BuildParam{},
+		Engine:       engine,
+	}
+	if env := q.Get("environment"); env != "" {
+		build.Environment = null.StringFrom(env)
+	}
+	for name, value := range inputs {
+		build.Params = append(build.Params, response.BuildParam{
+			BuildID: build.BuildID,
+			Name:    name,
+			Value:   fmt.Sprint(value),
+		})
+	}
+	s.builds[build.BuildID] = build
+	writeJSON(w, http.StatusOK, response.BuildReferenceWrapper{
+		BuildReference: strconv.FormatUint(uint64(build.BuildID), 10),
+	})
+}
+
+func (s *Server) resolveEngine(id string) (*response.Engine, bool) {
+	if id == "" {
+		return s.engines.DefaultEngine, true
+	}
+	for _, e := range s.engines.List {
+		if e.ID == id {
+			engine := e
+			return &engine, true
+		}
+	}
+	return nil, false
+}
+
+func (s *Server) getBuildList(w http.ResponseWriter, r *http.Request, _ []uint) {
+	q := listQuery{r.URL.Query()}
+	var ids []uint
+	for id, b := range s.builds {
+		if q.eqUint("projectId", b.ProjectID) &&
+			q.oneOf("status", string(b.Status)) &&
+			q.oneOf("statusId", strconv.Itoa(b.StatusID)) &&
+			q.eq("isInvalid", strconv.FormatBool(b.IsInvalid)) &&
+			q.eq("environment", b.Environment.String) &&
+			q.eq("gitBranch", b.GitBranch) &&
+			q.eq("stage", b.Stage) &&
+			q.match("environmentMatch", b.Environment.String) &&
+			q.match("gitBranchMatch", b.GitBranch) &&
+			q.match("stageMatch", b.Stage) &&
+			q.match("match", b.Environment.String, b.GitBranch, b.Stage) {
+			ids = append(ids, id)
+		}
+	}
+	start, end, ok := page(w, r, q, len(ids))
+	if !ok {
+		return
+	}
+	list := []response.Build{}
+	for _, id := range sortedIDs(ids)[start:end] {
+		list = append(list, s.fullBuild(s.builds[id]))
+	}
+	writeJSON(w, http.StatusOK, response.PaginatedBuilds{List: list, TotalCount: int64(len(ids))})
+}
+
+func (s *Server) getBuild(w http.ResponseWriter, r *http.Request, ids []uint) {
+	build, ok := s.builds[ids[0]]
+	if !ok {
+		writeNotFound(w, r, "build", ids[0])
+		return
+	}
+	writeJSON(w, http.StatusOK, s.fullBuild(build))
+}
+
+// fullBuild adds the build's test result summaries, as they are not kept up
+// to date in the stored build.
+func (s *Server) fullBuild(build response.Build) response.Build {
+	build.TestResultSummaries = s.buildSummaries(build.BuildID)
+	build.TestResultListSummary = listSummary(build.BuildID, build.TestResultSummaries)
+	return build
+}
+
+func (s *Server) updateBuildStatus(w http.ResponseWriter, r *http.Request, ids []uint) {
+	build, ok := s.builds[ids[0]]
+	if !ok {
+		writeNotFound(w, r, "build", ids[0])
+		return
+	}
+	var req request.LogOrStatusUpdate
+	if !readJSON(w, r, &req) {
+		return
+	}
+	status := response.BuildStatus(req.Status)
+	statusID, ok := buildStatusIDs[status]
+	if !ok {
+		writeProblem(w, r, http.StatusBadRequest, ProblemTypeInvalidBody,
+			"Invalid build status: "+strconv.Quote(string(req.Status)))
+		return
+	}
+	now := time.Now().UTC()
+	build.Status = status
+	build.StatusID = statusID
+	switch status {
+	case response.BuildRunning:
+		build.StartedOn = null.TimeFrom(now)
+	case response.BuildCompleted, response.BuildFailed:
+		build.CompletedOn = null.TimeFrom(now)
+	}
+	touch(&build.TimeMetadata)
+	s.builds[build.BuildID] = build
+	writeJSON(w, http.StatusOK, s.fullBuild(build))
+}
+
+func (s *Server) getLogList(w http.ResponseWriter, r *http.Request, ids []uint) {
+	if _, ok := s.builds[ids[0]]; !ok {
+		writeNotFound(w, r, "build", ids[0])
+		return
+	}
+	writeJSON(w, http.StatusOK, append([]response.Log{}, s.logs[ids[0]]...))
+}
+
+func (s *Server) createLog(w http.ResponseWriter, r *http.Request, ids []uint) {
+	if _, ok := s.builds[ids[0]]; !ok {
+		writeNotFound(w, r, "build", ids[0])
+		return
+	}
+	var req request.LogOrStatusUpdate
+	if !readJSON(w, r, &req) {
+		return
+	}
+	l := response.Log{
+		LogID:     s.newID(),
+		BuildID:   ids[0],
+		Message:   req.Message,
+		Timestamp: req.Timestamp,
+	}
+	s.logs[ids[0]] = append(s.logs[ids[0]], l)
+	writeJSON(w, http.StatusCreated, l)
+}
+
+func (s *Server) getArtifactList(w http.ResponseWriter, r *http.Request, ids []uint) {
+	if _, ok := s.builds[ids[0]]; !ok {
+		writeNotFound(w, r, "build", ids[0])
+		return
+	}
+	q := listQuery{r.URL.Query()}
+	var artifactIDs []uint
+	for id, a := range s.artifacts {
+		if a.BuildID == ids[0] &&
+			q.eq("name", a.Name) &&
+			q.eq("fileName", a.FileName) &&
+			q.match("nameMatch", a.Name) &&
+			q.match("fileNameMatch", a.FileName) &&
+			q.match("match", a.Name, a.FileName) {
+			artifactIDs = append(artifactIDs, id)
+		}
+	}
+	start, end, ok := page(w, r, q, len(artifactIDs))
+	if !ok {
+		return
+	}
+	list := []response.Artifact{}
+	for _, id := range sortedIDs(artifactIDs)[start:end] {
+		list = append(list, s.artifacts[id].Artifact)
+	}
+	writeJSON(w, http.StatusOK, response.PaginatedArtifacts{List: list, TotalCount: int64(len(artifactIDs))})
+}
+
+func (s *Server) getArtifact(w http.ResponseWriter, r *http.Request, ids []uint) {
+	a, ok := s.artifacts[ids[1]]
+	if !ok || a.BuildID != ids[0] {
+		writeNotFound(w, r, "artifact", ids[1])
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", a.FileName))
+	w.Header().Set("Content-Length", strconv.Itoa(len(a.data)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(a.data)
+}
+
+func (s *Server) createArtifacts(w http.ResponseWriter, r *http.Request, ids []uint) {
+	if _, ok := s.builds[ids[0]]; !ok {
+		writeNotFound(w, r, "build", ids[0])
+		return
+	}
+	files, ok := readFiles(w, r)
+	if !ok {
+		return
+	}
+	created := []response.ArtifactMetadata{}
+	for _, f := range files {
+		a := s.addArtifact(ids[0], f)
+		created = append(created, response.ArtifactMetadata{
+			TimeMetadata: a.TimeMetadata,
+			FileName:     a.FileName,
+			ArtifactID:   a.ArtifactID,
+		})
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *Server) addArtifact(buildID uint, f uploadedFile) artifact {
+	a := artifact{
+		Artifact: response.Artifact{
+			TimeMetadata: newTimeMetadata(),
+			ArtifactID:   s.newID(),
+			BuildID:      buildID,
+			Name:         f.name,
+			FileName:     f.name,
+		},
+		data: f.data,
+	}
+	s.artifacts[a.ArtifactID] = a
+	return a
+}
+
+type uploadedFile struct {
+	name string
+	data []byte
+}
+
+// readFiles reads all files of the "files" field of a multipart request.
+func readFiles(w http.ResponseWriter, r *http.Request) ([]uploadedFile, bool) {
+	if err := r.ParseMultipartForm(maxMultipartMemory); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, ProblemTypeInvalidBody,
+			"Failed to parse multipart form: "+err.Error())
+		return nil, false
+	}
+	defer r.MultipartForm.RemoveAll()
+	var files []uploadedFile
+	for _, header := range r.MultipartForm.File["files"] {
+		f, err := header.Open()
+		if err != nil {
+			writeProblem(w, r, http.StatusBadRequest, ProblemTypeInvalidBody,
+				"Failed to open uploaded file: "+err.Error())
+			return nil, false
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			writeProblem(w, r, http.StatusBadRequest, ProblemTypeInvalidBody,
+				"Failed to read uploaded file: "+err.Error())
+			return nil, false
+		}
+		files = append(files, uploadedFile{header.Filename, data})
+	}
+	if len(files) == 0 {
+		writeProblem(w, r, http.StatusBadRequest, ProblemTypeInvalidBody,
+			`No files were uploaded in the "files" field.`)
+		return nil, false
+	}
+	return files, true
+}
+
+func (s *Server) createTestResults(w http.ResponseWriter, r *http.Request, ids []uint) {
+	buildID := ids[0]
+	if _, ok := s.builds[buildID]; !ok {
+		writeNotFound(w, r, "build", buildID)
+		return
+	}
+	files, ok := readFiles(w, r)
+	if !ok {
+		return
+	}
+	// All files are parsed before any is stored, so nothing is stored if
+	// one of them is invalid.
+	results := make([]testresult.Result, len(files))
+	for i, f := range files {
+		res, err := testresult.ParseTRX(bytes.NewReader(f.data))
+		if err != nil {
+			writeProblem(w, r, http.StatusBadRequest, ProblemTypeInvalidBody,
+				fmt.Sprintf("Failed to parse test result file %q: %v", f.name, err))
+			return
+		}
+		results[i] = res
+	}
+	created := []response.ArtifactMetadata{}
+	for i, f := range files {
+		a := s.addArtifact(buildID, f)
+		summary := results[i].Summary
+		summary.TimeMetadata = a.TimeMetadata
+		summary.TestResultSummaryID = s.newID()
+		summary.FileName = a.FileName
+		summary.ArtifactID = a.ArtifactID
+		summary.BuildID = buildID
+		s.summaries[a.ArtifactID] = summary
+		details := []response.TestResultDetail{}
+		for _, d := range results[i].Details {
+			d.TimeMetadata = a.TimeMetadata
+			d.TestResultDetailID = s.newID()
+			d.ArtifactID = a.ArtifactID
+			d.BuildID = buildID
+			details = append(details, d)
+		}
+		s.testDetails[a.ArtifactID] = details
+		created = append(created, response.ArtifactMetadata{
+			TimeMetadata: a.TimeMetadata,
+			FileName:     a.FileName,
+			ArtifactID:   a.ArtifactID,
+		})
+	}
+	writeJSON(w, http.StatusCreated, created)
+}
+
+func (s *Server) buildSummaries(buildID uint) []response.TestResultSummary {
+	var artifactIDs []uint
+	for id, summary := range s.summaries {
+		if summary.BuildID == buildID {
+			artifactIDs = append(artifactIDs, id)
+		}
+	}
+	summaries := []response.TestResultSummary{}
+	for _, id := range sortedIDs(artifactIDs) {
+		summaries = append(summaries, s.summaries[id])
+	}
+	return summaries
+}
+
+func listSummary(buildID uint, summaries []response.TestResultSummary) response.TestResultListSummary {
+	sum := response.TestResultListSummary{BuildID: buildID}
+	for _, summary := range summaries {
+		sum.Total += summary.Total
+		sum.Failed += summary.Failed
+		sum.Passed += summary.Passed
+		sum.Skipped += summary.Skipped
+	}
+	return sum
+}
+
+func (s *Server) getTestResultListSummary(w http.ResponseWriter, r *http.Request, ids []uint) {
+	if _, ok := s.builds[ids[0]]; !ok {
+		writeNotFound(w, r, "build", ids[0])
+		return
+	}
+	writeJSON(w, http.StatusOK, listSummary(ids[0], s.buildSummaries(ids[0])))
+}
+
+func (s *Server) getTestResultSummaryList(w http.ResponseWriter, r *http.Request, ids []uint) {
+	if _, ok := s.builds[ids[0]]; !ok {
+		writeNotFound(w, r, "build", ids[0])
+		return
+	}
+	summaries := s.buildSummaries(ids[0])
+	writeJSON(w, http.StatusOK, response.PaginatedTestResultSummaries{
+		List:       summaries,
+		TotalCount: int64(len(summaries)),
+	})
+}
+
+func (s *Server) getTestResultSummary(w http.ResponseWriter, r *http.Request, ids []uint) {
+	summary, ok := s.summaries[ids[1]]
+	if !ok || summary.BuildID != ids[0] {
+		writeNotFound(w, r, "test result summary", ids[1])
+		return
+	}
+	writeJSON(w, http.StatusOK, summary)
+}
+
+func (s *Server) getBuildTestResultDetailList(w http.ResponseWriter, r *http.Request, ids []uint) {
+	if _, ok := s.builds[ids[0]]; !ok {
+		writeNotFound(w, r, "build", ids[0])
+		return
+	}
+	var details []response.TestResultDetail
+	for _, summary := range s.buildSummaries(ids[0]) {
+		details = append(details, s.testDetails[summary.ArtifactID]...)
+	}
+	writeTestResultDetails(w, r, details)
+}
+
+func (s *Server) getTestResultDetailList(w http.ResponseWriter, r *http.Request, ids []uint) {
+	summary, ok := s.summaries[ids[1]]
+	if !ok || summary.BuildID != ids[0] {
+		writeNotFound(w, r, "test result summary", ids[1])
+		return
+	}
+	writeTestResultDetails(w, r, s.testDetails[ids[1]])
+}
+
+func writeTestResultDetails(w http.ResponseWriter, r *http.Request, details []response.TestResultDetail) {
+	q := listQuery{r.URL.Query()}
+	var matched []response.TestResultDetail
+	for _, d := range details {
+		if q.oneOf("status", string(d.Status)) &&
+			q.eq("name", d.Name) &&
+			q.match("nameMatch", d.Name) {
+			matched = append(matched, d)
+		}
+	}
+	start, end, ok := page(w, r, q, len(matched))
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, response.PaginatedTestResultDetails{
+		List:       append([]response.TestResultDetail{}, matched[start:end]...),
+		TotalCount: int64(len(matched)),
+	})
+}
@@ -0,0 +1,273 @@
+package wharfapitest
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	v5 "github.com/iver-wharf/wharf-api-client-go/v2/api/wharfapi/v5"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/wharfapi"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// bufconnSize is the size of the in-process listener's buffer.
+const bufconnSize = 1 << 20
+
+// grpcAPIURL is used as the wharfapi.Client.APIURL when only using the
+// GRPCServer. The address is never resolved, as the connection is made
+// in-process.
+const grpcAPIURL = "http://bufconn"
+
+// StreamFault is an injected failure of a log creation stream.
+type StreamFault struct {
+	// AfterMessages is the number of messages received on the stream before
+	// it fails.
+	AfterMessages int
+	// Err is the error returned to the client. Use status.Error to set the
+	// gRPC status code. Defaults to an error with the code codes.Unavailable
+	// if nil.
+	Err error
+	// Times is the number of streams to fail, after which the fault is
+	// removed. Fails all streams if zero.
+	Times int
+}
+
+type logKey struct {
+	buildID      uint64
+	workerLogID  uint64
+	workerStepID uint64
+}
+
+// GRPCServer is an in-process fake of the wharf-api gRPC Builds service,
+// served over a bufconn listener. It implements v5.BuildsServer.
+//
+// Same as the real wharf-api, logs that target non-existing builds and logs
+// that have already been added before, based on their build, worker log, and
+// worker step IDs, are discarded.
+//
+// Safe for concurrent use.
+type GRPCServer struct {
+	v5.UnimplementedBuildsServer
+
+	lis  *bufconn.Listener
+	srv  *grpc.Server
+	http *Server
+
+	mu       sync.Mutex
+	delay    time.Duration
+	faults   []StreamFault
+	seen     map[logKey]struct{}
+	received []*v5.CreateLogStreamRequest
+	inserted []*v5.CreateLogStreamRequest
+}
+
+// NewGRPCServer starts a new, standalone, fake wharf-api gRPC server, where
+// every build is considered to exist. The server should be closed when done,
+// by calling Close.
+//
+// Use Server.StartGRPC instead to add the streamed logs to the builds of a
+// fake wharf-api.
+func NewGRPCServer() *GRPCServer {
+	return newGRPCServer(nil)
+}
+
+func newGRPCServer(http *Server) *GRPCServer {
+	g := &GRPCServer{
+		lis:  bufconn.Listen(bufconnSize),
+		srv:  grpc.NewServer(),
+		http: http,
+		seen: map[logKey]struct{}{},
+	}
+	v5.RegisterBuildsServer(g.srv, g)
+	go g.srv.Serve(g.lis)
+	return g
+}
+
+// StartGRPC starts a fake wharf-api gRPC server, where the streamed logs are
+// added to the builds of this server, and logs targeting builds not found in
+// this server are discarded. The gRPC server is closed together with this
+// server. Calling it multiple times returns the same gRPC server.
+//
+// The gRPC server should be started before calling Server.Client, so the
+// returned client can connect to it.
+func (s *Server) StartGRPC() *GRPCServer {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.grpc == nil {
+		s.grpc = newGRPCServer(s)
+	}
+	return s.grpc
+}
+
+// Close stops the server and closes all open streams.
+func (g *GRPCServer) Close() {
+	g.srv.Stop()
+}
+
+// DialOptions returns the options needed to connect to this server, for use
+// in wharfapi.Client.GRPCDialOptions.
+func (g *GRPCServer) DialOptions() []grpc.DialOption {
+	return []grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return g.lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}
+}
+
+// Client returns a new wharfapi.Client that connects to this server. It can
+// only be used for the gRPC methods, such as
+// wharfapi.Client.CreateBuildLogStream.
+func (g *GRPCServer) Client() *wharfapi.Client {
+	return &wharfapi.Client{
+		APIURL:                 grpcAPIURL,
+		DisableOutdatedLogging: true,
+		GRPCDialOptions:        g.DialOptions(),
+	}
+}
+
+// SetDelay slows down all streams, by sleeping before handling each received
+// message. Zero disables the delay.
+func (g *GRPCServer) SetDelay(delay time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.delay = delay
+}
+
+// InjectFault makes new streams fail. Faults are used in the order they were
+// injected.
+func (g *GRPCServer) InjectFault(fault StreamFault) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.faults = append(g.faults, fault)
+}
+
+// ClearFaults removes all injected faults.
+func (g *GRPCServer) ClearFaults() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.faults = nil
+}
+
+// Received returns all received log creation requests, including the
+// discarded ones, in the order they were received.
+func (g *GRPCServer) Received() []*v5.CreateLogStreamRequest {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]*v5.CreateLogStreamRequest(nil), g.received...)
+}
+
+// Inserted returns the received log creation requests that were not
+// discarded, in the order they were received.
+func (g *GRPCServer) Inserted() []*v5.CreateLogStreamRequest {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]*v5.CreateLogStreamRequest(nil), g.inserted...)
+}
+
+// CreateLogStream receives logs until the client closes the stream, and
+// responds with the number of logs that were not discarded. Implements
+// v5.BuildsServer.
+func (g *GRPCServer) CreateLogStream(stream v5.Builds_CreateLogStreamServer) error {
+	fault, hasFault := g.takeFault()
+	var received int
+	var inserted uint64
+	for {
+		if hasFault && received >= fault.AfterMessages {
+			if fault.Err != nil {
+				return fault.Err
+			}
+			return status.Error(codes.Unavailable, "fault injected")
+		}
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return stream.SendAndClose(&v5.CreateLogStreamResponse{
+				LinesInserted: inserted,
+			})
+		}
+		if err != nil {
+			return err
+		}
+		received++
+		if err := g.sleep(stream.Context()); err != nil {
+			return err
+		}
+		if g.insert(req) {
+			inserted++
+		}
+	}
+}
+
+func (g *GRPCServer) takeFault() (StreamFault, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if len(g.faults) == 0 {
+		return StreamFault{}, false
+	}
+	fault := g.faults[0]
+	if fault.Times > 0 {
+		g.faults[0].Times--
+		if g.faults[0].Times == 0 {
+			g.faults = g.faults[1:]
+		}
+	}
+	return fault, true
+}
+
+func (g *GRPCServer) sleep(ctx context.Context) error {
+	g.mu.Lock()
+	delay := g.delay
+	g.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+func (g *GRPCServer) insert(req *v5.CreateLogStreamRequest) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.received = append(g.received, req)
+	key := logKey{req.BuildID, req.WorkerLogID, req.WorkerStepID}
+	if _, ok := g.seen[key]; ok {
+		return false
+	}
+	if g.http != nil && !g.http.addStreamedLog(req) {
+		return false
+	}
+	g.seen[key] = struct{}{}
+	g.inserted = append(g.inserted, req)
+	return true
+}
+
+// addStreamedLog adds the log to its build, or returns false if the build
+// does not exist.
+func (s *Server) addStreamedLog(req *v5.CreateLogStreamRequest) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	buildID := uint(req.BuildID)
+	if _, ok := s.builds[buildID]; !ok {
+		return false
+	}
+	s.logs[buildID] = append(s.logs[buildID], response.Log{
+		LogID:     s.newID(),
+		BuildID:   buildID,
+		Message:   req.Message,
+		Timestamp: req.Timestamp.AsTime(),
+	})
+	return true
+}
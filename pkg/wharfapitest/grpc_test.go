@@ -0,0 +1,106 @@
+package wharfapitest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/request"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestGRPCServer_discardsDuplicates(t *testing.T) {
+	g := NewGRPCServer()
+	defer g.Close()
+	c := g.Client()
+
+	stream, err := c.CreateBuildLogStream(context.Background())
+	require.NoError(t, err)
+	logs := []request.Log{
+		{BuildID: 1, WorkerStepID: 1, WorkerLogID: 1, Message: "first"},
+		{BuildID: 1, WorkerStepID: 1, WorkerLogID: 2, Message: "second"},
+		{BuildID: 1, WorkerStepID: 1, WorkerLogID: 1, Message: "duplicate"},
+		{BuildID: 1, WorkerStepID: 2, WorkerLogID: 1, Message: "other step"},
+	}
+	for _, l := range logs {
+		require.NoError(t, stream.Send(l))
+	}
+	summary, err := stream.CloseAndRecv()
+	require.NoError(t, err)
+	assert.EqualValues(t, 3, summary.LogsInserted)
+	assert.Len(t, g.Received(), 4)
+	var messages []string
+	for _, req := range g.Inserted() {
+		messages = append(messages, req.Message)
+	}
+	assert.Equal(t, []string{"first", "second", "other step"}, messages)
+}
+
+func TestServer_StartGRPC(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.StartGRPC()
+	c := srv.Client()
+
+	project, err := c.CreateProject(request.Project{Name: "api"})
+	require.NoError(t, err)
+	build := srv.AddBuild(response.Build{ProjectID: project.ProjectID})
+
+	stream, err := c.CreateBuildLogStream(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(request.Log{BuildID: build.BuildID, WorkerLogID: 1, Message: "hello"}))
+	require.NoError(t, stream.Send(request.Log{BuildID: build.BuildID + 100, WorkerLogID: 1, Message: "missing build"}))
+	summary, err := stream.CloseAndRecv()
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, summary.LogsInserted)
+
+	logs, err := c.GetBuildLogList(build.BuildID)
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "hello", logs[0].Message)
+}
+
+func TestGRPCServer_faults(t *testing.T) {
+	g := NewGRPCServer()
+	defer g.Close()
+	c := g.Client()
+	g.InjectFault(StreamFault{
+		AfterMessages: 1,
+		Err:           status.Error(codes.ResourceExhausted, "too many logs"),
+		Times:         1,
+	})
+
+	stream, err := c.CreateBuildLogStream(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(request.Log{BuildID: 1, WorkerLogID: 1}))
+	// Sends may or may not fail, depending on when the server closes the
+	// stream, but the error is always returned when closing.
+	stream.Send(request.Log{BuildID: 1, WorkerLogID: 2})
+	_, err = stream.CloseAndRecv()
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err), "got: %v", err)
+
+	stream, err = c.CreateBuildLogStream(context.Background())
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(request.Log{BuildID: 1, WorkerLogID: 3}))
+	_, err = stream.CloseAndRecv()
+	assert.NoError(t, err, "fault should only fail once")
+}
+
+func TestGRPCServer_delay(t *testing.T) {
+	g := NewGRPCServer()
+	defer g.Close()
+	c := g.Client()
+	g.SetDelay(time.Second)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	stream, err := c.CreateBuildLogStream(ctx)
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(request.Log{BuildID: 1, WorkerLogID: 1}))
+	_, err = stream.CloseAndRecv()
+	assert.Equal(t, codes.DeadlineExceeded, status.Code(err), "got: %v", err)
+}
@@ -0,0 +1,98 @@
+package wharfapitest
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// listQuery holds the query parameters of a list endpoint.
+type listQuery struct {
+	url.Values
+}
+
+// eq returns true if the parameter is not set, or is equal to the value.
+func (q listQuery) eq(key, value string) bool {
+	v, ok := q.Values[key]
+	return !ok || v[0] == value
+}
+
+// eqUint returns true if the parameter is not set, or is equal to the value.
+func (q listQuery) eqUint(key string, value uint) bool {
+	return q.eq(key, strconv.FormatUint(uint64(value), 10))
+}
+
+// oneOf returns true if the parameter is not set, or if any of its values is
+// equal to the value.
+func (q listQuery) oneOf(key, value string) bool {
+	values, ok := q.Values[key]
+	if !ok {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// match returns true if the parameter is not set, or is a case-insensitive
+// substring of any of the values.
+func (q listQuery) match(key string, values ...string) bool {
+	v, ok := q.Values[key]
+	if !ok {
+		return true
+	}
+	needle := strings.ToLower(v[0])
+	for _, value := range values {
+		if strings.Contains(strings.ToLower(value), needle) {
+			return true
+		}
+	}
+	return false
+}
+
+// page returns the bounds of the page of a list of n items, as requested by
+// the limit and offset query parameters. A problem is written, and false is
+// returned, if the parameters are invalid.
+func page(w http.ResponseWriter, r *http.Request, q listQuery, n int) (start, end int, ok bool) {
+	limit, offset := n, 0
+	if v := q.Get("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil || l < 0 {
+			writeProblem(w, r, http.StatusBadRequest, ProblemTypeInvalidParam,
+				"Invalid limit: "+strconv.Quote(v))
+			return 0, 0, false
+		}
+		// Zero means no limit, same as in the wharf-api.
+		if l > 0 {
+			limit = l
+		}
+	}
+	if v := q.Get("offset"); v != "" {
+		o, err := strconv.Atoi(v)
+		if err != nil || o < 0 {
+			writeProblem(w, r, http.StatusBadRequest, ProblemTypeInvalidParam,
+				"Invalid offset: "+strconv.Quote(v))
+			return 0, 0, false
+		}
+		offset = o
+	}
+	start = offset
+	if start > n {
+		start = n
+	}
+	end = start + limit
+	if end > n {
+		end = n
+	}
+	return start, end, true
+}
+
+func sortedIDs(ids []uint) []uint {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
@@ -0,0 +1,410 @@
+package wharfapitest
+
+import (
+	"net/http"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/request"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+)
+
+func (s *Server) getTokenList(w http.ResponseWriter, r *http.Request, _ []uint) {
+	q := listQuery{r.URL.Query()}
+	var ids []uint
+	for id, t := range s.tokens {
+		if q.eq("userName", t.UserName) &&
+			q.match("userNameMatch", t.UserName) {
+			ids = append(ids, id)
+		}
+	}
+	start, end, ok := page(w, r, q, len(ids))
+	if !ok {
+		return
+	}
+	list := []response.Token{}
+	for _, id := range sortedIDs(ids)[start:end] {
+		list = append(list, s.tokens[id])
+	}
+	writeJSON(w, http.StatusOK, response.PaginatedTokens{List: list, TotalCount: int64(len(ids))})
+}
+
+func (s *Server) getToken(w http.ResponseWriter, r *http.Request, ids []uint) {
+	token, ok := s.tokens[ids[0]]
+	if !ok {
+		writeNotFound(w, r, "token", ids[0])
+		return
+	}
+	writeJSON(w, http.StatusOK, token)
+}
+
+func (s *Server) createToken(w http.ResponseWriter, r *http.Request, _ []uint) {
+	var req request.Token
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.Token == "" || req.UserName == "" {
+		writeProblem(w, r, http.StatusBadRequest, ProblemTypeInvalidBody,
+			"The token and userName fields are required.")
+		return
+	}
+	token := response.Token{
+		TimeMetadata: newTimeMetadata(),
+		TokenID:      s.newID(),
+		Token:        req.Token,
+		UserName:     req.UserName,
+	}
+	s.tokens[token.TokenID] = token
+	if provider, ok := s.providers[req.ProviderID]; ok {
+		provider.TokenID = token.TokenID
+		touch(&provider.TimeMetadata)
+		s.providers[provider.ProviderID] = provider
+	}
+	writeJSON(w, http.StatusCreated, token)
+}
+
+func (s *Server) updateToken(w http.ResponseWriter, r *http.Request, ids []uint) {
+	token, ok := s.tokens[ids[0]]
+	if !ok {
+		writeNotFound(w, r, "token", ids[0])
+		return
+	}
+	var req request.TokenUpdate
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.Token == "" || req.UserName == "" {
+		writeProblem(w, r, http.StatusBadRequest, ProblemTypeInvalidBody,
+			"The token and userName fields are required.")
+		return
+	}
+	token.Token = req.Token
+	token.UserName = req.UserName
+	touch(&token.TimeMetadata)
+	s.tokens[token.TokenID] = token
+	writeJSON(w, http.StatusOK, token)
+}
+
+func (s *Server) getProviderList(w http.ResponseWriter, r *http.Request, _ []uint) {
+	q := listQuery{r.URL.Query()}
+	var ids []uint
+	for id, p := range s.providers {
+		if q.eq("name", string(p.Name)) &&
+			q.eq("url", p.URL) &&
+			q.eqUint("tokenId", p.TokenID) &&
+			q.match("nameMatch", string(p.Name)) &&
+			q.match("urlMatch", p.URL) &&
+			q.match("match", string(p.Name), p.URL) {
+			ids = append(ids, id)
+		}
+	}
+	start, end, ok := page(w, r, q, len(ids))
+	if !ok {
+		return
+	}
+	list := []response.Provider{}
+	for _, id := range sortedIDs(ids)[start:end] {
+		list = append(list, s.providers[id])
+	}
+	writeJSON(w, http.StatusOK, response.PaginatedProviders{List: list, TotalCount: int64(len(ids))})
+}
+
+func (s *Server) getProvider(w http.ResponseWriter, r *http.Request, ids []uint) {
+	provider, ok := s.providers[ids[0]]
+	if !ok {
+		writeNotFound(w, r, "provider", ids[0])
+		return
+	}
+	writeJSON(w, http.StatusOK, provider)
+}
+
+func (s *Server) createProvider(w http.ResponseWriter, r *http.Request, _ []uint) {
+	var req request.Provider
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if !validProvider(w, r, req.Name, req.URL) {
+		return
+	}
+	provider := response.Provider{
+		TimeMetadata: newTimeMetadata(),
+		ProviderID:   s.newID(),
+		Name:         response.ProviderName(req.Name),
+		URL:          req.URL,
+		TokenID:      req.TokenID,
+	}
+	s.providers[provider.ProviderID] = provider
+	writeJSON(w, http.StatusCreated, provider)
+}
+
+func (s *Server) updateProvider(w http.ResponseWriter, r *http.Request, ids []uint) {
+	provider, ok := s.providers[ids[0]]
+	if !ok {
+		writeNotFound(w, r, "provider", ids[0])
+		return
+	}
+	var req request.ProviderUpdate
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if !validProvider(w, r, req.Name, req.URL) {
+		return
+	}
+	provider.Name = response.ProviderName(req.Name)
+	provider.URL = req.URL
+	provider.TokenID = req.TokenID
+	touch(&provider.TimeMetadata)
+	s.providers[provider.ProviderID] = provider
+	writeJSON(w, http.StatusOK, provider)
+}
+
+func validProvider(w http.ResponseWriter, r *http.Request, name request.ProviderName, url string) bool {
+	if !name.IsValid() {
+		writeProblem(w, r, http.StatusBadRequest, ProblemTypeInvalidBody,
+			"Invalid provider name, must be one of: "+string(request.ProviderNameValues))
+		return false
+	}
+	if url == "" {
+		writeProblem(w, r, http.StatusBadRequest, ProblemTypeInvalidBody,
+			"The url field is required.")
+		return false
+	}
+	return true
+}
+
+func (s *Server) getProjectList(w http.ResponseWriter, r *http.Request, _ []uint) {
+	q := listQuery{r.URL.Query()}
+	var ids []uint
+	for id, p := range s.projects {
+		if q.eq("name", p.Name) &&
+			q.eq("groupName", p.GroupName) &&
+			q.eq("description", p.Description) &&
+			q.eq("gitUrl", p.GitURL) &&
+			q.eqUint("tokenId", p.TokenID) &&
+			q.eqUint("providerId", p.ProviderID) &&
+			q.match("nameMatch", p.Name) &&
+			q.match("groupNameMatch", p.GroupName) &&
+			q.match("descriptionMatch", p.Description) &&
+			q.match("gitUrlMatch", p.GitURL) &&
+			q.match("match", p.Name, p.GroupName, p.Description, p.GitURL) {
+			ids = append(ids, id)
+		}
+	}
+	start, end, ok := page(w, r, q, len(ids))
+	if !ok {
+		return
+	}
+	list := []response.Project{}
+	for _, id := range sortedIDs(ids)[start:end] {
+		list = append(list, s.fullProject(s.projects[id]))
+	}
+	writeJSON(w, http.StatusOK, response.PaginatedProjects{List: list, TotalCount: int64(len(ids))})
+}
+
+func (s *Server) getProject(w http.ResponseWriter, r *http.Request, ids []uint) {
+	project, ok := s.projects[ids[0]]
+	if !ok {
+		writeNotFound(w, r, "project", ids[0])
+		return
+	}
+	writeJSON(w, http.StatusOK, s.fullProject(project))
+}
+
+// fullProject adds the project's provider and branches, as they are not kept
+// up to date in the stored project.
+func (s *Server) fullProject(project response.Project) response.Project {
+	if provider, ok := s.providers[project.ProviderID]; ok {
+		project.Provider = &provider
+	}
+	project.Branches = append([]response.Branch{}, s.branches[project.ProjectID]...)
+	return project
+}
+
+func (s *Server) createProject(w http.ResponseWriter, r *http.Request, _ []uint) {
+	var req request.Project
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeProblem(w, r, http.StatusBadRequest, ProblemTypeInvalidBody,
+			"The name field is required.")
+		return
+	}
+	project := response.Project{
+		TimeMetadata:    newTimeMetadata(),
+		ProjectID:       s.newID(),
+		RemoteProjectID: req.RemoteProjectID,
+		Name:            req.Name,
+		GroupName:       req.GroupName,
+		Description:     req.Description,
+		AvatarURL:       req.AvatarURL,
+		TokenID:         req.TokenID,
+		ProviderID:      req.ProviderID,
+		BuildDefinition: req.BuildDefinition,
+		GitURL:          req.GitURL,
+	}
+	s.projects[project.ProjectID] = project
+	writeJSON(w, http.StatusCreated, s.fullProject(project))
+}
+
+func (s *Server) updateProject(w http.ResponseWriter, r *http.Request, ids []uint) {
+	project, ok := s.projects[ids[0]]
+	if !ok {
+		writeNotFound(w, r, "project", ids[0])
+		return
+	}
+	var req request.ProjectUpdate
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeProblem(w, r, http.StatusBadRequest, ProblemTypeInvalidBody,
+			"The name field is required.")
+		return
+	}
+	project.Name = req.Name
+	project.GroupName = req.GroupName
+	project.Description = req.Description
+	project.AvatarURL = req.AvatarURL
+	project.TokenID = req.TokenID
+	project.ProviderID = req.ProviderID
+	project.BuildDefinition = req.BuildDefinition
+	project.GitURL = req.GitURL
+	touch(&project.TimeMetadata)
+	s.projects[project.ProjectID] = project
+	writeJSON(w, http.StatusOK, s.fullProject(project))
+}
+
+func (s *Server) deleteProject(w http.ResponseWriter, r *http.Request, ids []uint) {
+	projectID := ids[0]
+	if _, ok := s.projects[projectID]; !ok {
+		writeNotFound(w, r, "project", projectID)
+		return
+	}
+	delete(s.projects, projectID)
+	delete(s.overrides, projectID)
+	delete(s.branches, projectID)
+	for buildID, build := range s.builds {
+		if build.ProjectID == projectID {
+			s.deleteBuild(buildID)
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getProjectOverrides(w http.ResponseWriter, r *http.Request, ids []uint) {
+	if _, ok := s.projects[ids[0]]; !ok {
+		writeNotFound(w, r, "project", ids[0])
+		return
+	}
+	overrides, ok := s.overrides[ids[0]]
+	if !ok {
+		overrides.ProjectID = ids[0]
+	}
+	writeJSON(w, http.StatusOK, overrides)
+}
+
+func (s *Server) updateProjectOverrides(w http.ResponseWriter, r *http.Request, ids []uint) {
+	if _, ok := s.projects[ids[0]]; !ok {
+		writeNotFound(w, r, "project", ids[0])
+		return
+	}
+	var req request.ProjectOverridesUpdate
+	if !readJSON(w, r, &req) {
+		return
+	}
+	overrides := response.ProjectOverrides{
+		ProjectID:   ids[0],
+		Description: req.Description,
+		AvatarURL:   req.AvatarURL,
+		GitURL:      req.GitURL,
+	}
+	s.overrides[ids[0]] = overrides
+	writeJSON(w, http.StatusOK, overrides)
+}
+
+func (s *Server) deleteProjectOverrides(w http.ResponseWriter, r *http.Request, ids []uint) {
+	if _, ok := s.projects[ids[0]]; !ok {
+		writeNotFound(w, r, "project", ids[0])
+		return
+	}
+	delete(s.overrides, ids[0])
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getBranchList(w http.ResponseWriter, r *http.Request, ids []uint) {
+	if _, ok := s.projects[ids[0]]; !ok {
+		writeNotFound(w, r, "project", ids[0])
+		return
+	}
+	writeJSON(w, http.StatusOK, append([]response.Branch{}, s.branches[ids[0]]...))
+}
+
+func (s *Server) createBranch(w http.ResponseWriter, r *http.Request, ids []uint) {
+	project, ok := s.projects[ids[0]]
+	if !ok {
+		writeNotFound(w, r, "project", ids[0])
+		return
+	}
+	var req request.Branch
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.Name == "" {
+		writeProblem(w, r, http.StatusBadRequest, ProblemTypeInvalidBody,
+			"The name field is required.")
+		return
+	}
+	branches := s.branches[project.ProjectID]
+	if req.Default {
+		for i := range branches {
+			branches[i].Default = false
+		}
+	}
+	branch := response.Branch{
+		TimeMetadata: newTimeMetadata(),
+		BranchID:     s.newID(),
+		ProjectID:    project.ProjectID,
+		Name:         req.Name,
+		Default:      req.Default,
+		TokenID:      project.TokenID,
+	}
+	s.branches[project.ProjectID] = append(branches, branch)
+	writeJSON(w, http.StatusCreated, branch)
+}
+
+func (s *Server) updateBranchList(w http.ResponseWriter, r *http.Request, ids []uint) {
+	project, ok := s.projects[ids[0]]
+	if !ok {
+		writeNotFound(w, r, "project", ids[0])
+		return
+	}
+	var req request.BranchListUpdate
+	if !readJSON(w, r, &req) {
+		return
+	}
+	existing := map[string]response.Branch{}
+	for _, b := range s.branches[project.ProjectID] {
+		existing[b.Name] = b
+	}
+	list := response.BranchList{Branches: []response.Branch{}}
+	for _, b := range req.Branches {
+		branch, ok := existing[b.Name]
+		if !ok {
+			branch = response.Branch{
+				TimeMetadata: newTimeMetadata(),
+				BranchID:     s.newID(),
+				ProjectID:    project.ProjectID,
+				Name:         b.Name,
+				TokenID:      project.TokenID,
+			}
+		}
+		branch.Default = b.Name == req.DefaultBranch
+		list.Branches = append(list.Branches, branch)
+	}
+	for i, b := range list.Branches {
+		if b.Default {
+			list.DefaultBranch = &list.Branches[i]
+		}
+	}
+	s.branches[project.ProjectID] = list.Branches
+	writeJSON(w, http.StatusOK, list)
+}
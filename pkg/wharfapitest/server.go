@@ -0,0 +1,404 @@
+// Package wharfapitest provides an in-memory fake of the wharf-api, for
+// testing code that uses a wharfapi.Client without a real wharf-api and
+// database.
+//
+// The fake implements the endpoints called by the wharfapi.Client, with all
+// state kept in memory. Errors are responded with RFC 7807 problem responses,
+// the same way as the real wharf-api does.
+//
+// Usage:
+//  srv := wharfapitest.NewServer()
+//  defer srv.Close()
+//  client := srv.Client()
+//  token, err := client.CreateToken(request.Token{Token: "secret", UserName: "bot"})
+//
+// The fake is not a full reimplementation of the wharf-api. Only the simpler
+// filters of the list endpoints are supported, and the results are always
+// ordered by their IDs.
+package wharfapitest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/wharfapi"
+	"github.com/iver-wharf/wharf-core/pkg/app"
+	"github.com/iver-wharf/wharf-core/pkg/problem"
+)
+
+// DefaultVersion is the wharf-api version reported by a new Server. It is
+// new enough to support all endpoints of the wharfapi.Client.
+const DefaultVersion = "v5.1.0"
+
+// Problem types used in the responded problems.
+const (
+	ProblemTypeNotFound         = "/prob/api/record-not-found"
+	ProblemTypeInvalidParam     = "/prob/api/invalid-param"
+	ProblemTypeInvalidBody      = "/prob/api/invalid-body"
+	ProblemTypeMethodNotAllowed = "/prob/api/method-not-allowed"
+	ProblemTypeFault            = "/prob/api/fault-injected"
+)
+
+// Fault is an injected failure, where matching requests are responded with a
+// problem instead of being handled.
+type Fault struct {
+	// Method is the HTTP method to match, or any method if empty.
+	Method string
+	// Path is a pattern of the request paths to match, using the syntax of
+	// path.Match, such as "/api/project/*". Matches any path if empty.
+	Path string
+	// Status is the HTTP status code of the response. Defaults to
+	// http.StatusInternalServerError if zero.
+	Status int
+	// Detail is the detail message of the responded problem.
+	Detail string
+	// Times is the number of requests to fail, after which the fault is
+	// removed. Fails all matching requests if zero.
+	Times int
+}
+
+func (f Fault) matches(r *http.Request) bool {
+	if f.Method != "" && !strings.EqualFold(f.Method, r.Method) {
+		return false
+	}
+	if f.Path == "" {
+		return true
+	}
+	ok, _ := path.Match(f.Path, r.URL.Path)
+	return ok
+}
+
+// Server is an in-memory fake of the wharf-api.
+//
+// Safe for concurrent use.
+type Server struct {
+	// URL is the base URL of the server, for use in wharfapi.Client.APIURL.
+	URL string
+
+	srv *httptest.Server
+
+	mu          sync.Mutex
+	grpc        *GRPCServer
+	version     app.Version
+	health      response.HealthStatus
+	engines     response.EngineList
+	faults      []Fault
+	nextID      uint
+	tokens      map[uint]response.Token
+	providers   map[uint]response.Provider
+	projects    map[uint]response.Project
+	overrides   map[uint]response.ProjectOverrides
+	branches    map[uint][]response.Branch
+	builds      map[uint]response.Build
+	logs        map[uint][]response.Log
+	artifacts   map[uint]artifact
+	summaries   map[uint]response.TestResultSummary
+	testDetails map[uint][]response.TestResultDetail
+}
+
+type artifact struct {
+	response.Artifact
+	data []byte
+}
+
+// NewServer starts a new, empty, fake wharf-api. The server should be closed
+// when done, by calling Close.
+func NewServer() *Server {
+	s := &Server{
+		version: app.Version{Version: DefaultVersion},
+		health: response.HealthStatus{
+			Message:   "API is healthy.",
+			IsHealthy: true,
+		},
+		nextID:      1,
+		tokens:      map[uint]response.Token{},
+		providers:   map[uint]response.Provider{},
+		projects:    map[uint]response.Project{},
+		overrides:   map[uint]response.ProjectOverrides{},
+		branches:    map[uint][]response.Branch{},
+		builds:      map[uint]response.Build{},
+		logs:        map[uint][]response.Log{},
+		artifacts:   map[uint]artifact{},
+		summaries:   map[uint]response.TestResultSummary{},
+		testDetails: map[uint][]response.TestResultDetail{},
+	}
+	s.srv = httptest.NewServer(s)
+	s.URL = s.srv.URL
+	return s
+}
+
+// Close shuts down the server, and its gRPC server if started.
+func (s *Server) Close() {
+	s.srv.Close()
+	s.mu.Lock()
+	grpc := s.grpc
+	s.mu.Unlock()
+	if grpc != nil {
+		grpc.Close()
+	}
+}
+
+// Client returns a new wharfapi.Client that sends its requests to this
+// server, and to its gRPC server if started.
+func (s *Server) Client() *wharfapi.Client {
+	c := &wharfapi.Client{
+		APIURL:                 s.URL,
+		DisableOutdatedLogging: true,
+		HTTPClient:             s.srv.Client(),
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.grpc != nil {
+		c.GRPCDialOptions = s.grpc.DialOptions()
+	}
+	return c
+}
+
+// SetVersion sets the version responded by the GET /api/version endpoint.
+// Defaults to DefaultVersion.
+func (s *Server) SetVersion(version app.Version) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.version = version
+}
+
+// SetHealth sets the health responded by the GET /api/health endpoint.
+// Defaults to being healthy.
+func (s *Server) SetHealth(health response.HealthStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.health = health
+}
+
+// SetEngines sets the execution engines responded by the GET /api/engine
+// endpoint and used when starting builds. Defaults to no engines.
+func (s *Server) SetEngines(engines response.EngineList) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.engines = engines
+}
+
+// InjectFault makes matching requests fail. Faults are matched in the order
+// they were injected.
+func (s *Server) InjectFault(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, fault)
+}
+
+// ClearFaults removes all injected faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// ServeHTTP handles a request to the fake wharf-api. Implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if fault, ok := s.takeFault(r); ok {
+		status := fault.Status
+		if status == 0 {
+			status = http.StatusInternalServerError
+		}
+		writeProblem(w, r, status, ProblemTypeFault, fault.Detail)
+		return
+	}
+	segments := splitPath(r.URL.Path)
+	pathMatched := false
+	for _, rt := range routes {
+		ids, ok := rt.match(segments)
+		if !ok {
+			continue
+		}
+		pathMatched = true
+		if rt.method != r.Method {
+			continue
+		}
+		rt.handle(s, w, r, ids)
+		return
+	}
+	if pathMatched {
+		writeProblem(w, r, http.StatusMethodNotAllowed, ProblemTypeMethodNotAllowed,
+			"Method "+r.Method+" is not allowed on this path.")
+		return
+	}
+	writeProblem(w, r, http.StatusNotFound, ProblemTypeNotFound, "No such endpoint.")
+}
+
+func (s *Server) takeFault(r *http.Request) (Fault, bool) {
+	for i, fault := range s.faults {
+		if !fault.matches(r) {
+			continue
+		}
+		if fault.Times > 0 {
+			s.faults[i].Times--
+			if s.faults[i].Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return fault, true
+	}
+	return Fault{}, false
+}
+
+func (s *Server) newID() uint {
+	id := s.nextID
+	s.nextID++
+	return id
+}
+
+type handlerFunc func(s *Server, w http.ResponseWriter, r *http.Request, ids []uint)
+
+// route is an endpoint, where the path segments "{id}" matches unsigned
+// integers, which are passed on to the handler in order.
+type route struct {
+	method   string
+	segments []string
+	handle   handlerFunc
+}
+
+func newRoute(method, pattern string, handle handlerFunc) route {
+	return route{method, splitPath(pattern), handle}
+}
+
+func (rt route) match(segments []string) ([]uint, bool) {
+	if len(segments) != len(rt.segments) {
+		return nil, false
+	}
+	var ids []uint
+	for i, seg := range rt.segments {
+		if seg != "{id}" {
+			if seg != segments[i] {
+				return nil, false
+			}
+			continue
+		}
+		id, err := strconv.ParseUint(segments[i], 10, 0)
+		if err != nil {
+			return nil, false
+		}
+		ids = append(ids, uint(id))
+	}
+	return ids, true
+}
+
+func splitPath(p string) []string {
+	return strings.Split(strings.Trim(p, "/"), "/")
+}
+
+var routes = []route{
+	newRoute(http.MethodGet, "/api/version", (*Server).getVersion),
+	newRoute(http.MethodGet, "/api/health", (*Server).getHealth),
+	newRoute(http.MethodGet, "/api/ping", (*Server).getPing),
+	newRoute(http.MethodGet, "/api/engine", (*Server).getEngineList),
+
+	newRoute(http.MethodGet, "/api/token", (*Server).getTokenList),
+	newRoute(http.MethodPost, "/api/token", (*Server).createToken),
+	newRoute(http.MethodGet, "/api/token/{id}", (*Server).getToken),
+	newRoute(http.MethodPut, "/api/token/{id}", (*Server).updateToken),
+
+	newRoute(http.MethodGet, "/api/provider", (*Server).getProviderList),
+	newRoute(http.MethodPost, "/api/provider", (*Server).createProvider),
+	newRoute(http.MethodGet, "/api/provider/{id}", (*Server).getProvider),
+	newRoute(http.MethodPut, "/api/provider/{id}", (*Server).updateProvider),
+
+	newRoute(http.MethodGet, "/api/project", (*Server).getProjectList),
+	newRoute(http.MethodPost, "/api/project", (*Server).createProject),
+	newRoute(http.MethodGet, "/api/project/{id}", (*Server).getProject),
+	newRoute(http.MethodPut, "/api/project/{id}", (*Server).updateProject),
+	newRoute(http.MethodDelete, "/api/project/{id}", (*Server).deleteProject),
+	newRoute(http.MethodGet, "/api/project/{id}/override", (*Server).getProjectOverrides),
+	newRoute(http.MethodPut, "/api/project/{id}/override", (*Server).updateProjectOverrides),
+	newRoute(http.MethodDelete, "/api/project/{id}/override", (*Server).deleteProjectOverrides),
+	newRoute(http.MethodGet, "/api/project/{id}/branch", (*Server).getBranchList),
+	newRoute(http.MethodPost, "/api/project/{id}/branch", (*Server).createBranch),
+	newRoute(http.MethodPut, "/api/project/{id}/branch", (*Server).updateBranchList),
+	newRoute(http.MethodPost, "/api/project/{id}/build", (*Server).startBuild),
+
+	newRoute(http.MethodGet, "/api/build", (*Server).getBuildList),
+	newRoute(http.MethodGet, "/api/build/{id}", (*Server).getBuild),
+	newRoute(http.MethodPut, "/api/build/{id}/status", (*Server).updateBuildStatus),
+	newRoute(http.MethodGet, "/api/build/{id}/log", (*Server).getLogList),
+	newRoute(http.MethodPost, "/api/build/{id}/log", (*Server).createLog),
+	newRoute(http.MethodGet, "/api/build/{id}/artifact", (*Server).getArtifactList),
+	newRoute(http.MethodPost, "/api/build/{id}/artifact", (*Server).createArtifacts),
+	newRoute(http.MethodGet, "/api/build/{id}/artifact/{id}", (*Server).getArtifact),
+	newRoute(http.MethodPost, "/api/build/{id}/test-result", (*Server).createTestResults),
+	newRoute(http.MethodGet, "/api/build/{id}/test-result/detail", (*Server).getBuildTestResultDetailList),
+	newRoute(http.MethodGet, "/api/build/{id}/test-result/list-summary", (*Server).getTestResultListSummary),
+	newRoute(http.MethodGet, "/api/build/{id}/test-result/summary", (*Server).getTestResultSummaryList),
+	newRoute(http.MethodGet, "/api/build/{id}/test-result/summary/{id}", (*Server).getTestResultSummary),
+	newRoute(http.MethodGet, "/api/build/{id}/test-result/summary/{id}/detail", (*Server).getTestResultDetailList),
+}
+
+func (s *Server) getVersion(w http.ResponseWriter, r *http.Request, _ []uint) {
+	writeJSON(w, http.StatusOK, s.version)
+}
+
+func (s *Server) getHealth(w http.ResponseWriter, r *http.Request, _ []uint) {
+	writeJSON(w, http.StatusOK, s.health)
+}
+
+func (s *Server) getPing(w http.ResponseWriter, r *http.Request, _ []uint) {
+	writeJSON(w, http.StatusOK, response.Ping{Message: "pong"})
+}
+
+func (s *Server) getEngineList(w http.ResponseWriter, r *http.Request, _ []uint) {
+	list := s.engines
+	if list.List == nil {
+		list.List = []response.Engine{}
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func writeJSON(w http.ResponseWriter, status int, obj interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(obj)
+}
+
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, probType, detail string) {
+	prob := problem.Response{
+		Type:     "https://" + problem.DocsHost + "/#" + probType,
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: r.URL.Path,
+	}
+	w.Header().Set("Content-Type", problem.HTTPContentType)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(prob)
+}
+
+func writeNotFound(w http.ResponseWriter, r *http.Request, kind string, id uint) {
+	writeProblem(w, r, http.StatusNotFound, ProblemTypeNotFound,
+		"No "+kind+" with ID "+strconv.FormatUint(uint64(id), 10)+" was found.")
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, obj interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(obj); err != nil {
+		writeProblem(w, r, http.StatusBadRequest, ProblemTypeInvalidBody,
+			"Failed to parse request body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func newTimeMetadata() response.TimeMetadata {
+	now := time.Now().UTC()
+	return response.TimeMetadata{CreatedAt: &now, UpdatedAt: &now}
+}
+
+func touch(meta *response.TimeMetadata) {
+	now := time.Now().UTC()
+	meta.UpdatedAt = &now
+}
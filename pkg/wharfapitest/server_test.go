@@ -0,0 +1,168 @@
+package wharfapitest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/request"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/wharfapi"
+	"github.com/iver-wharf/wharf-core/pkg/app"
+	"github.com/iver-wharf/wharf-core/pkg/problem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTRX = `<?xml version="1.0" encoding="utf-8"?>
+<TestRun id="1" name="run" xmlns="http://microsoft.com/schemas/VisualStudio/TeamTest/2010">
+  <Results>
+    <UnitTestResult testId="a" testName="Foo.Passes" outcome="Passed" duration="00:00:01.5000000" />
+    <UnitTestResult testId="b" testName="Foo.Fails" outcome="Failed" duration="00:00:00.2500000" />
+  </Results>
+</TestRun>`
+
+func TestServer_projects(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	c := srv.Client()
+	ctx := context.Background()
+
+	token, err := c.CreateToken(request.Token{Token: "secret", UserName: "bot"})
+	require.NoError(t, err)
+	provider, err := c.CreateProvider(request.Provider{
+		Name:    request.ProviderGitHub,
+		URL:     "https://github.com",
+		TokenID: token.TokenID,
+	})
+	require.NoError(t, err)
+	project, outcome, err := c.EnsureProject(ctx, request.Project{
+		Name:       "api",
+		GroupName:  "team",
+		TokenID:    token.TokenID,
+		ProviderID: provider.ProviderID,
+		GitURL:     "git@github.com:team/api.git",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, wharfapi.EnsureCreated, outcome)
+
+	_, err = c.CreateProjectBranch(project.ProjectID, request.Branch{Name: "main", Default: true})
+	require.NoError(t, err)
+	found, err := c.FindProjectByGitURL(ctx, "https://github.com/team/api")
+	require.NoError(t, err)
+	assert.Equal(t, project.ProjectID, found.ProjectID)
+	require.NotNil(t, found.Provider)
+	assert.Equal(t, provider.ProviderID, found.Provider.ProviderID)
+	require.Len(t, found.Branches, 1)
+	assert.True(t, found.Branches[0].Default)
+
+	_, err = c.UpdateProjectOverrides(project.ProjectID, request.ProjectOverridesUpdate{Description: "overridden"})
+	require.NoError(t, err)
+	effective, err := c.GetEffectiveProject(project.ProjectID)
+	require.NoError(t, err)
+	assert.Equal(t, "overridden", effective.Project.Description)
+
+	require.NoError(t, c.DeleteProject(project.ProjectID))
+	_, err = c.GetProject(project.ProjectID)
+	var prob problem.Response
+	require.True(t, errors.As(err, &prob), "got: %v", err)
+	assert.Equal(t, http.StatusNotFound, prob.Status)
+}
+
+func TestServer_builds(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.SetEngines(response.EngineList{
+		List: []response.Engine{{ID: "primary", Name: "Primary"}},
+	})
+	c := srv.Client()
+
+	project, err := c.CreateProject(request.Project{Name: "api"})
+	require.NoError(t, err)
+	ref, err := c.StartProjectBuild(project.ProjectID, wharfapi.ProjectStartBuild{
+		Stage:  "deploy",
+		Branch: "main",
+		Engine: "primary",
+	}, request.BuildInputs{"version": "1.0"})
+	require.NoError(t, err)
+	buildID, err := strconv.ParseUint(ref.BuildReference, 10, 0)
+	require.NoError(t, err)
+
+	build, err := c.UpdateBuildStatus(uint(buildID), request.LogOrStatusUpdate{Status: request.BuildRunning})
+	require.NoError(t, err)
+	assert.Equal(t, response.BuildRunning, build.Status)
+	require.NotNil(t, build.Engine)
+	assert.Equal(t, "primary", build.Engine.ID)
+	require.Len(t, build.Params, 1)
+	assert.Equal(t, "1.0", build.Params[0].Value)
+
+	require.NoError(t, c.CreateBuildLog(uint(buildID), request.LogOrStatusUpdate{Message: "hello"}))
+	logs, err := c.GetBuildLogList(uint(buildID))
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	assert.Equal(t, "hello", logs[0].Message)
+
+	require.NoError(t, c.CreateBuildArtifact(uint(buildID), "out.txt", strings.NewReader("content")))
+	artifacts, err := c.GetBuildArtifactListAll(wharfapi.ArtifactSearch{}, uint(buildID))
+	require.NoError(t, err)
+	require.Len(t, artifacts, 1)
+	body, err := c.GetBuildArtifact(uint(buildID), artifacts[0].ArtifactID)
+	require.NoError(t, err)
+	data, err := io.ReadAll(body)
+	body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "content", string(data))
+
+	_, err = c.CreateBuildTestResult(uint(buildID), "tests.trx", strings.NewReader(testTRX))
+	require.NoError(t, err)
+	summary, err := c.GetBuildAllTestResultListSummary(uint(buildID))
+	require.NoError(t, err)
+	assert.Equal(t, response.TestResultListSummary{BuildID: uint(buildID), Total: 2, Passed: 1, Failed: 1}, summary)
+
+	running := string(response.BuildRunning)
+	builds, err := c.GetBuildList(wharfapi.BuildSearch{Status: []string{running}})
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, builds.TotalCount)
+}
+
+func TestServer_faults(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	c := srv.Client()
+
+	srv.InjectFault(Fault{
+		Method: http.MethodGet,
+		Path:   "/api/project/*",
+		Status: http.StatusServiceUnavailable,
+		Detail: "Database is down.",
+		Times:  1,
+	})
+	project, err := c.CreateProject(request.Project{Name: "api"})
+	require.NoError(t, err)
+
+	_, err = c.GetProject(project.ProjectID)
+	var prob problem.Response
+	require.True(t, errors.As(err, &prob), "got: %v", err)
+	assert.Equal(t, http.StatusServiceUnavailable, prob.Status)
+	assert.Equal(t, "Database is down.", prob.Detail)
+
+	_, err = c.GetProject(project.ProjectID)
+	assert.NoError(t, err, "fault should only fail once")
+}
+
+func TestServer_version(t *testing.T) {
+	srv := NewServer()
+	defer srv.Close()
+	srv.SetVersion(app.Version{Version: "v4.2.0"})
+	c := srv.Client()
+	c.ErrIfOutdatedServer = true
+
+	_, err := c.Ping()
+	require.NoError(t, err)
+	_, err = c.GetBuildList(wharfapi.BuildSearch{})
+	assert.ErrorIs(t, err, wharfapi.ErrOutdatedServer)
+}
@@ -0,0 +1,164 @@
+// Package wharfbackup exports and restores the configuration of a Wharf
+// instance, meaning its providers, tokens, projects, project overrides, and
+// project branches. Builds, logs, and artifacts are not included.
+//
+// The backup is a versioned JSON lines archive, with one record per line. It
+// contains the tokens' secrets, as they are needed when restoring, so the
+// backup must be stored as securely as the Wharf database itself.
+package wharfbackup
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/wharfapi"
+)
+
+// FormatVersion is the version of the backup format written by Export. Backups
+// of newer versions are rejected by Restore.
+const FormatVersion = 1
+
+// ErrUnsupportedVersion is returned when restoring a backup with a newer
+// format version than FormatVersion.
+var ErrUnsupportedVersion = errors.New("unsupported backup format version")
+
+// Kind is an enum of record types in a backup.
+type Kind string
+
+const (
+	// KindHeader is the first record of a backup, holding its metadata.
+	KindHeader Kind = "header"
+	// KindToken is a token record.
+	KindToken Kind = "token"
+	// KindProvider is a provider record.
+	KindProvider Kind = "provider"
+	// KindProject is a project record, without its overrides and branches.
+	KindProject Kind = "project"
+	// KindProjectOverrides is a project's overrides record.
+	KindProjectOverrides Kind = "projectOverrides"
+	// KindProjectBranches is a project's branches record.
+	KindProjectBranches Kind = "projectBranches"
+)
+
+// Header holds metadata about a backup.
+type Header struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	APIURL    string    `json:"apiUrl"`
+}
+
+// ProjectBranches holds all branches of a project.
+type ProjectBranches struct {
+	ProjectID uint              `json:"projectId"`
+	Branches  []response.Branch `json:"branches"`
+}
+
+// Record is a single line in a backup, where only the field matching the Kind
+// is set.
+type Record struct {
+	Kind      Kind                       `json:"kind"`
+	Header    *Header                    `json:"header,omitempty"`
+	Token     *response.Token            `json:"token,omitempty"`
+	Provider  *response.Provider         `json:"provider,omitempty"`
+	Project   *response.Project          `json:"project,omitempty"`
+	Overrides *response.ProjectOverrides `json:"overrides,omitempty"`
+	Branches  *ProjectBranches           `json:"branches,omitempty"`
+}
+
+// Stats holds the number of records per kind, excluding the header.
+type Stats map[Kind]int
+
+// Export writes all providers, tokens, projects, project overrides, and
+// project branches of the Wharf instance to w as JSON lines.
+//
+// Records are written in dependency order, so that tokens come before the
+// providers that reference them, and both come before the projects, which in
+// turn come before their overrides and branches.
+func Export(c *wharfapi.Client, w io.Writer) (Stats, error) {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	stats := Stats{}
+	write := func(rec Record) error {
+		if rec.Kind != KindHeader {
+			stats[rec.Kind]++
+		}
+		return enc.Encode(rec)
+	}
+
+	if err := write(Record{Kind: KindHeader, Header: &Header{
+		Version:   FormatVersion,
+		CreatedAt: time.Now().UTC(),
+		APIURL:    c.APIURL,
+	}}); err != nil {
+		return stats, err
+	}
+
+	tokens, err := c.GetTokenListAll(wharfapi.TokenSearch{
+		OrderBy: []string{response.TokenJSONFields.TokenID},
+	})
+	if err != nil {
+		return stats, fmt.Errorf("get token list: %w", err)
+	}
+	for i := range tokens {
+		if err := write(Record{Kind: KindToken, Token: &tokens[i]}); err != nil {
+			return stats, err
+		}
+	}
+
+	providers, err := c.GetProviderListAll(wharfapi.ProviderSearch{
+		OrderBy: []string{response.ProviderJSONFields.ProviderID},
+	})
+	if err != nil {
+		return stats, fmt.Errorf("get provider list: %w", err)
+	}
+	for i := range providers {
+		if err := write(Record{Kind: KindProvider, Provider: &providers[i]}); err != nil {
+			return stats, err
+		}
+	}
+
+	projects, err := c.GetProjectListAll(wharfapi.ProjectSearch{
+		OrderBy: []string{response.ProjectJSONFields.ProjectID},
+	})
+	if err != nil {
+		return stats, fmt.Errorf("get project list: %w", err)
+	}
+	for _, p := range projects {
+		if err := exportProject(c, write, p); err != nil {
+			return stats, err
+		}
+	}
+	return stats, bw.Flush()
+}
+
+func exportProject(c *wharfapi.Client, write func(Record) error, p response.Project) error {
+	// The provider, branches, and parsed build definition are either stored
+	// in other records or derived by wharf-api, so leave them out.
+	p.Provider = nil
+	p.Branches = nil
+	p.ParsedBuildDefinition = nil
+	if err := write(Record{Kind: KindProject, Project: &p}); err != nil {
+		return err
+	}
+	overrides, err := c.GetProjectOverrides(p.ProjectID)
+	if err != nil {
+		return fmt.Errorf("project %d: get overrides: %w", p.ProjectID, err)
+	}
+	overrides.ProjectID = p.ProjectID
+	if err := write(Record{Kind: KindProjectOverrides, Overrides: &overrides}); err != nil {
+		return err
+	}
+	branches, err := c.GetProjectBranchList(p.ProjectID)
+	if err != nil {
+		return fmt.Errorf("project %d: get branches: %w", p.ProjectID, err)
+	}
+	return write(Record{Kind: KindProjectBranches, Branches: &ProjectBranches{
+		ProjectID: p.ProjectID,
+		Branches:  branches,
+	}})
+}
@@ -0,0 +1,124 @@
+package wharfbackup
+
+import (
+	"bytes"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/request"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/wharfapi"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/wharfapitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestServer(t *testing.T) (*wharfapitest.Server, *wharfapi.Client) {
+	srv := wharfapitest.NewServer()
+	t.Cleanup(srv.Close)
+	return srv, srv.Client()
+}
+
+// newTestSourceAPI returns a client to a fake wharf-api with a token (ID 1),
+// provider (ID 2), and project (ID 3) with overrides and branches.
+func newTestSourceAPI(t *testing.T) *wharfapi.Client {
+	_, c := newTestServer(t)
+	token, err := c.CreateToken(request.Token{Token: "secret", UserName: "bot"})
+	require.NoError(t, err)
+	provider, err := c.CreateProvider(request.Provider{
+		Name: request.ProviderGitLab, URL: "https://gitlab.example.com", TokenID: token.TokenID})
+	require.NoError(t, err)
+	project, err := c.CreateProject(request.Project{Name: "api", GroupName: "team",
+		TokenID: token.TokenID, ProviderID: provider.ProviderID,
+		BuildDefinition: "build:\n  steps: []\n"})
+	require.NoError(t, err)
+	_, err = c.UpdateProjectOverrides(project.ProjectID, request.ProjectOverridesUpdate{Description: "Overridden"})
+	require.NoError(t, err)
+	_, err = c.UpdateProjectBranchList(project.ProjectID, []request.Branch{{Name: "main", Default: true}})
+	require.NoError(t, err)
+	require.Equal(t, []uint{1, 2, 3}, []uint{token.TokenID, provider.ProviderID, project.ProjectID})
+	return c
+}
+
+func TestExportRestore(t *testing.T) {
+	var backup bytes.Buffer
+	stats, err := Export(newTestSourceAPI(t), &backup)
+	require.NoError(t, err)
+	assert.Equal(t, Stats{
+		KindToken:            1,
+		KindProvider:         1,
+		KindProject:          1,
+		KindProjectOverrides: 1,
+		KindProjectBranches:  1,
+	}, stats)
+	assert.Contains(t, strings.SplitN(backup.String(), "\n", 2)[0], `"version":1`)
+
+	_, c := newTestServer(t)
+	// Offsets the IDs in the target, so the remapping is tested.
+	_, err = c.CreateToken(request.Token{Token: "other", UserName: "other"})
+	require.NoError(t, err)
+	result, err := Restore(c, &backup, RestoreOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, stats, result.Restored)
+
+	tokenID, _ := result.NewID(KindToken, 1)
+	providerID, _ := result.NewID(KindProvider, 2)
+	projectID, _ := result.NewID(KindProject, 3)
+	assert.Equal(t, []uint{2, 3, 4}, []uint{tokenID, providerID, projectID})
+	provider, err := c.GetProvider(providerID)
+	require.NoError(t, err)
+	assert.Equal(t, tokenID, provider.TokenID)
+	project, err := c.GetProject(projectID)
+	require.NoError(t, err)
+	assert.Equal(t, tokenID, project.TokenID)
+	assert.Equal(t, providerID, project.ProviderID)
+	assert.Equal(t, "build:\n  steps: []\n", project.BuildDefinition)
+	overrides, err := c.GetProjectOverrides(projectID)
+	require.NoError(t, err)
+	assert.Equal(t, "Overridden", overrides.Description)
+	branches, err := c.GetProjectBranchList(projectID)
+	require.NoError(t, err)
+	require.Len(t, branches, 1)
+	assert.True(t, branches[0].Default)
+}
+
+func TestRestore_resume(t *testing.T) {
+	var backup bytes.Buffer
+	_, err := Export(newTestSourceAPI(t), &backup)
+	require.NoError(t, err)
+	progressLog := filepath.Join(t.TempDir(), "progress.jsonl")
+
+	srv, c := newTestServer(t)
+	srv.InjectFault(wharfapitest.Fault{Method: http.MethodPost, Path: "/api/project"})
+	_, err = Restore(c, bytes.NewReader(backup.Bytes()), RestoreOptions{ProgressLog: progressLog})
+	require.Error(t, err)
+	tokens, err := c.GetTokenListAll(wharfapi.TokenSearch{})
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	providers, err := c.GetProviderListAll(wharfapi.ProviderSearch{})
+	require.NoError(t, err)
+	require.Len(t, providers, 1)
+	projects, err := c.GetProjectListAll(wharfapi.ProjectSearch{})
+	require.NoError(t, err)
+	assert.Empty(t, projects)
+
+	srv.ClearFaults()
+	result, err := Restore(c, bytes.NewReader(backup.Bytes()), RestoreOptions{ProgressLog: progressLog})
+	require.NoError(t, err)
+	assert.Equal(t, Stats{KindToken: 1, KindProvider: 1}, result.Skipped)
+	tokensAfter, err := c.GetTokenListAll(wharfapi.TokenSearch{})
+	require.NoError(t, err)
+	assert.Len(t, tokensAfter, 1, "should not recreate token")
+	projects, err = c.GetProjectListAll(wharfapi.ProjectSearch{})
+	require.NoError(t, err)
+	require.Len(t, projects, 1)
+	assert.Equal(t, tokens[0].TokenID, projects[0].TokenID)
+	assert.Equal(t, providers[0].ProviderID, projects[0].ProviderID)
+}
+
+func TestRestore_unsupportedVersion(t *testing.T) {
+	_, c := newTestServer(t)
+	_, err := Restore(c, strings.NewReader(`{"kind":"header","header":{"version":99}}`), RestoreOptions{})
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+}
@@ -0,0 +1,273 @@
+package wharfbackup
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/request"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/wharfapi"
+)
+
+// maxRecordSize is the maximum size of a single line in a backup. Projects
+// embed their build definitions, which may be large.
+const maxRecordSize = 16 * 1024 * 1024
+
+// RestoreOptions holds optional settings used when restoring a backup.
+type RestoreOptions struct {
+	// ProgressLog, if set, is the path to a JSON lines file where each
+	// restored record is logged together with its old and new ID. If the file
+	// already exists, then the records logged in it are skipped, and their new
+	// IDs are reused when remapping references, so an interrupted restore can
+	// be resumed by restoring the same backup again with the same progress log.
+	//
+	// A record is logged after it has been restored, so a record that was
+	// being restored when the restore was interrupted may be restored twice.
+	ProgressLog string
+}
+
+// RestoreResult holds the outcome of a restore.
+type RestoreResult struct {
+	Header Header
+	// Restored is the number of records restored, per kind.
+	Restored Stats
+	// Skipped is the number of records skipped as they were already restored
+	// according to the progress log, per kind.
+	Skipped Stats
+	// IDMap maps the old IDs from the backup to the new IDs for the
+	// KindToken, KindProvider, and KindProject kinds.
+	IDMap map[Kind]map[uint]uint
+}
+
+// NewID returns the new ID of a restored token, provider, or project.
+func (r RestoreResult) NewID(kind Kind, oldID uint) (uint, bool) {
+	id, ok := r.IDMap[kind][oldID]
+	return id, ok
+}
+
+type progressEntry struct {
+	Kind  Kind `json:"kind"`
+	OldID uint `json:"oldId"`
+	NewID uint `json:"newId"`
+}
+
+type progressKey struct {
+	kind  Kind
+	oldID uint
+}
+
+// Restore recreates the records of a backup, as written by Export, on the
+// Wharf instance. As the new instance assigns new IDs, all references between
+// the records, such as a project's token ID and provider ID, are rewritten to
+// the new IDs.
+//
+// Restore expects an instance without the backup's providers, tokens, and
+// projects, as it always creates new ones.
+func Restore(c *wharfapi.Client, r io.Reader, opts RestoreOptions) (RestoreResult, error) {
+	result := RestoreResult{
+		Restored: Stats{},
+		Skipped:  Stats{},
+		IDMap: map[Kind]map[uint]uint{
+			KindToken:    {},
+			KindProvider: {},
+			KindProject:  {},
+		},
+	}
+	done := map[progressKey]bool{}
+	var progress io.Writer
+	if opts.ProgressLog != "" {
+		f, err := openProgressLog(opts.ProgressLog, &result, done)
+		if err != nil {
+			return result, err
+		}
+		defer f.Close()
+		progress = f
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxRecordSize)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return result, fmt.Errorf("line %d: decode record: %w", lineNum, err)
+		}
+		if lineNum == 1 {
+			if err := checkHeader(rec); err != nil {
+				return result, err
+			}
+			result.Header = *rec.Header
+			continue
+		}
+		oldID, err := recordID(rec)
+		if err != nil {
+			return result, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		key := progressKey{rec.Kind, oldID}
+		if done[key] {
+			result.Skipped[rec.Kind]++
+			continue
+		}
+		newID, err := restoreRecord(c, rec, result.IDMap)
+		if err != nil {
+			return result, fmt.Errorf("line %d: restore %s %d: %w", lineNum, rec.Kind, oldID, err)
+		}
+		if ids, ok := result.IDMap[rec.Kind]; ok {
+			ids[oldID] = newID
+		}
+		done[key] = true
+		result.Restored[rec.Kind]++
+		if progress != nil {
+			if err := json.NewEncoder(progress).Encode(progressEntry{rec.Kind, oldID, newID}); err != nil {
+				return result, fmt.Errorf("write progress log: %w", err)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return result, fmt.Errorf("read backup: %w", err)
+	}
+	if lineNum == 0 {
+		return result, errors.New("read backup: missing header")
+	}
+	return result, nil
+}
+
+func openProgressLog(path string, result *RestoreResult, done map[progressKey]bool) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("open progress log: %w", err)
+	}
+	dec := json.NewDecoder(f)
+	for {
+		var entry progressEntry
+		if err := dec.Decode(&entry); err == io.EOF {
+			break
+		} else if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("read progress log: %w", err)
+		}
+		done[progressKey{entry.Kind, entry.OldID}] = true
+		if ids, ok := result.IDMap[entry.Kind]; ok {
+			ids[entry.OldID] = entry.NewID
+		}
+	}
+	return f, nil
+}
+
+func checkHeader(rec Record) error {
+	if rec.Kind != KindHeader || rec.Header == nil {
+		return fmt.Errorf("line 1: expected %q record, got %q", KindHeader, rec.Kind)
+	}
+	if rec.Header.Version > FormatVersion {
+		return fmt.Errorf("%w: %d, only up to version %d is supported",
+			ErrUnsupportedVersion, rec.Header.Version, FormatVersion)
+	}
+	return nil
+}
+
+func recordID(rec Record) (uint, error) {
+	switch {
+	case rec.Kind == KindToken && rec.Token != nil:
+		return rec.Token.TokenID, nil
+	case rec.Kind == KindProvider && rec.Provider != nil:
+		return rec.Provider.ProviderID, nil
+	case rec.Kind == KindProject && rec.Project != nil:
+		return rec.Project.ProjectID, nil
+	case rec.Kind == KindProjectOverrides && rec.Overrides != nil:
+		return rec.Overrides.ProjectID, nil
+	case rec.Kind == KindProjectBranches && rec.Branches != nil:
+		return rec.Branches.ProjectID, nil
+	default:
+		return 0, fmt.Errorf("invalid %q record", rec.Kind)
+	}
+}
+
+// restoreRecord recreates a single record, and returns its new ID. For project
+// overrides and branches, the new ID is the ID of the project they belong to.
+func restoreRecord(c *wharfapi.Client, rec Record, idMap map[Kind]map[uint]uint) (uint, error) {
+	remap := func(kind Kind, oldID uint) (uint, error) {
+		if oldID == 0 {
+			return 0, nil
+		}
+		newID, ok := idMap[kind][oldID]
+		if !ok {
+			return 0, fmt.Errorf("references %s %d, which has not been restored", kind, oldID)
+		}
+		return newID, nil
+	}
+
+	switch rec.Kind {
+	case KindToken:
+		token, err := c.CreateToken(request.Token{
+			Token:    rec.Token.Token,
+			UserName: rec.Token.UserName,
+		})
+		return token.TokenID, err
+
+	case KindProvider:
+		tokenID, err := remap(KindToken, rec.Provider.TokenID)
+		if err != nil {
+			return 0, err
+		}
+		provider, err := c.CreateProvider(request.Provider{
+			Name:    request.ProviderName(rec.Provider.Name),
+			URL:     rec.Provider.URL,
+			TokenID: tokenID,
+		})
+		return provider.ProviderID, err
+
+	case KindProject:
+		p := rec.Project
+		tokenID, err := remap(KindToken, p.TokenID)
+		if err != nil {
+			return 0, err
+		}
+		providerID, err := remap(KindProvider, p.ProviderID)
+		if err != nil {
+			return 0, err
+		}
+		project, err := c.CreateProject(request.Project{
+			Name:            p.Name,
+			GroupName:       p.GroupName,
+			Description:     p.Description,
+			AvatarURL:       p.AvatarURL,
+			TokenID:         tokenID,
+			ProviderID:      providerID,
+			BuildDefinition: p.BuildDefinition,
+			GitURL:          p.GitURL,
+			RemoteProjectID: p.RemoteProjectID,
+		})
+		return project.ProjectID, err
+
+	case KindProjectOverrides:
+		projectID, err := remap(KindProject, rec.Overrides.ProjectID)
+		if err != nil {
+			return 0, err
+		}
+		_, err = c.UpdateProjectOverrides(projectID, request.ProjectOverridesUpdate{
+			Description: rec.Overrides.Description,
+			AvatarURL:   rec.Overrides.AvatarURL,
+			GitURL:      rec.Overrides.GitURL,
+		})
+		return projectID, err
+
+	case KindProjectBranches:
+		projectID, err := remap(KindProject, rec.Branches.ProjectID)
+		if err != nil {
+			return 0, err
+		}
+		branches := make([]request.Branch, 0, len(rec.Branches.Branches))
+		for _, b := range rec.Branches.Branches {
+			branches = append(branches, request.Branch{Name: b.Name, Default: b.Default})
+		}
+		_, err = c.UpdateProjectBranchList(projectID, branches)
+		return projectID, err
+
+	default:
+		return 0, fmt.Errorf("unknown record kind %q", rec.Kind)
+	}
+}
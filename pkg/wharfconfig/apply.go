@@ -0,0 +1,77 @@
+package wharfconfig
+
+import (
+	"fmt"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/request"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/wharfapi"
+)
+
+// ApplyResult holds the changes that were applied, in the order they were
+// applied.
+type ApplyResult struct {
+	Applied []ProjectChange
+}
+
+// Apply makes the changes of the plan in wharf-api, in the order they are
+// listed in the plan, and stops at the first error. Projects without changes
+// are skipped, and only the parts of a project that differ are updated.
+//
+// Applying is idempotent: if some changes fail to apply, then creating a new
+// plan and applying it again will only redo the changes that remain.
+func Apply(c *wharfapi.Client, plan Plan) (ApplyResult, error) {
+	var result ApplyResult
+	for _, change := range plan.Changes {
+		if change.Action == ActionNoop {
+			continue
+		}
+		applied, err := applyChange(c, change)
+		if err != nil {
+			return result, fmt.Errorf("%s project %q: %w", change.Action, change.Key(), err)
+		}
+		result.Applied = append(result.Applied, applied)
+	}
+	return result, nil
+}
+
+func applyChange(c *wharfapi.Client, change ProjectChange) (ProjectChange, error) {
+	switch change.Action {
+	case ActionCreate:
+		p := change.project
+		created, err := c.CreateProject(request.Project{
+			Name:            p.Name,
+			GroupName:       p.GroupName,
+			Description:     p.Description,
+			AvatarURL:       p.AvatarURL,
+			TokenID:         p.TokenID,
+			ProviderID:      p.ProviderID,
+			BuildDefinition: p.BuildDefinition,
+			GitURL:          p.GitURL,
+		})
+		if err != nil {
+			return change, err
+		}
+		change.ProjectID = created.ProjectID
+	case ActionUpdate:
+		if change.updateProject {
+			if _, err := c.UpdateProject(change.ProjectID, change.project); err != nil {
+				return change, err
+			}
+		}
+	case ActionDelete:
+		return change, c.DeleteProject(change.ProjectID)
+	default:
+		return change, fmt.Errorf("unknown action %q", change.Action)
+	}
+	if change.updateOverrides {
+		if _, err := c.UpdateProjectOverrides(change.ProjectID, change.overrides); err != nil {
+			return change, fmt.Errorf("update overrides: %w", err)
+		}
+	}
+	if change.updateBranches {
+		if _, err := c.UpdateProjectBranchList(change.ProjectID, change.branches); err != nil {
+			return change, fmt.Errorf("update branches: %w", err)
+		}
+	}
+	return change, nil
+}
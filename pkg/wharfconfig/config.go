@@ -0,0 +1,195 @@
+// Package wharfconfig reconciles Wharf projects against a declarative
+// desired-state configuration, similar to "terraform plan" and
+// "terraform apply".
+//
+// Providers and tokens are only referenced from the configuration, and must
+// already exist in wharf-api, as the configuration is not meant to hold any
+// secrets. Projects are created, updated, and, only when explicitly asked to,
+// deleted.
+package wharfconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/request"
+)
+
+// ErrInvalidConfig is returned when the configuration fails validation.
+var ErrInvalidConfig = errors.New("invalid config")
+
+// Config is the desired state of Wharf projects.
+type Config struct {
+	Providers []Provider `json:"providers"`
+	Tokens    []Token    `json:"tokens"`
+	Projects  []Project  `json:"projects"`
+}
+
+// Provider is a reference to an existing provider in wharf-api, looked up by
+// its name and URL.
+type Provider struct {
+	// Ref is the name used when referencing this provider from projects and
+	// tokens in the same configuration.
+	Ref  string               `json:"ref"`
+	Name request.ProviderName `json:"name"`
+	URL  string               `json:"url"`
+}
+
+// Token is a reference to an existing token in wharf-api, looked up by its
+// user name.
+type Token struct {
+	// Ref is the name used when referencing this token from projects in the
+	// same configuration.
+	Ref      string `json:"ref"`
+	UserName string `json:"userName"`
+}
+
+// Project is the desired state of a single project, identified by its group
+// name and name.
+type Project struct {
+	Name            string `json:"name"`
+	GroupName       string `json:"groupName"`
+	Description     string `json:"description"`
+	AvatarURL       string `json:"avatarUrl"`
+	GitURL          string `json:"gitUrl"`
+	BuildDefinition string `json:"buildDefinition"`
+	// Provider is the Ref of a provider in the same configuration. The
+	// project's provider is left as-is if empty.
+	Provider string `json:"provider"`
+	// Token is the Ref of a token in the same configuration. The project's
+	// token is left as-is if empty.
+	Token string `json:"token"`
+	// Overrides, if set, are the desired project overrides. The project's
+	// overrides are left as-is if nil.
+	Overrides *Overrides `json:"overrides"`
+	// Branches, if not empty, are the desired branches. The project's branches
+	// are left as-is if empty.
+	Branches []Branch `json:"branches"`
+}
+
+// Key returns the project's group name and name, which uniquely identifies it
+// among the projects in wharf-api.
+func (p Project) Key() string {
+	return projectKey(p.GroupName, p.Name)
+}
+
+func projectKey(groupName, name string) string {
+	if groupName == "" {
+		return name
+	}
+	return groupName + "/" + name
+}
+
+// Overrides is the desired state of a project's overrides.
+type Overrides struct {
+	Description string `json:"description"`
+	AvatarURL   string `json:"avatarUrl"`
+	GitURL      string `json:"gitUrl"`
+}
+
+// Branch is the desired state of a single project branch.
+type Branch struct {
+	Name    string `json:"name"`
+	Default bool   `json:"default"`
+}
+
+// Load reads and validates a JSON configuration file.
+func Load(path string) (Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Config{}, err
+	}
+	defer f.Close()
+	cfg, err := Decode(f)
+	if err != nil {
+		return Config{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// Decode reads and validates a JSON configuration. Unknown fields are treated
+// as errors, to catch typos that would otherwise silently be ignored.
+func Decode(r io.Reader) (Config, error) {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var cfg Config
+	if err := dec.Decode(&cfg); err != nil {
+		return Config{}, fmt.Errorf("decode config: %w", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, err
+	}
+	return cfg, nil
+}
+
+// Validate checks for missing required fields, duplicate references and
+// projects, and references to undeclared providers and tokens.
+func (cfg Config) Validate() error {
+	var problems []string
+	addf := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+	providers := map[string]bool{}
+	for i, p := range cfg.Providers {
+		switch {
+		case p.Ref == "":
+			addf("providers[%d]: missing ref", i)
+		case providers[p.Ref]:
+			addf("providers[%d]: duplicate ref %q", i, p.Ref)
+		}
+		if !p.Name.IsValid() {
+			addf("providers[%d]: invalid name %q, must be one of: %s", i, p.Name, request.ProviderNameValues)
+		}
+		if p.URL == "" {
+			addf("providers[%d]: missing url", i)
+		}
+		providers[p.Ref] = true
+	}
+	tokens := map[string]bool{}
+	for i, t := range cfg.Tokens {
+		switch {
+		case t.Ref == "":
+			addf("tokens[%d]: missing ref", i)
+		case tokens[t.Ref]:
+			addf("tokens[%d]: duplicate ref %q", i, t.Ref)
+		}
+		if t.UserName == "" {
+			addf("tokens[%d]: missing userName", i)
+		}
+		tokens[t.Ref] = true
+	}
+	projects := map[string]bool{}
+	for i, p := range cfg.Projects {
+		if p.Name == "" {
+			addf("projects[%d]: missing name", i)
+			continue
+		}
+		if projects[p.Key()] {
+			addf("projects[%d]: duplicate project %q", i, p.Key())
+		}
+		projects[p.Key()] = true
+		if p.Provider != "" && !providers[p.Provider] {
+			addf("projects[%d]: unknown provider ref %q", i, p.Provider)
+		}
+		if p.Token != "" && !tokens[p.Token] {
+			addf("projects[%d]: unknown token ref %q", i, p.Token)
+		}
+		var defaults int
+		for _, b := range p.Branches {
+			if b.Default {
+				defaults++
+			}
+		}
+		if defaults > 1 {
+			addf("projects[%d]: %d default branches, expected at most 1", i, defaults)
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("%w: %s", ErrInvalidConfig, strings.Join(problems, "; "))
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package wharfconfig
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	cfg, err := Decode(strings.NewReader(`{
+		"providers": [{"ref": "gh", "name": "github", "url": "https://api.github.com"}],
+		"tokens": [{"ref": "bot", "userName": "wharf-bot"}],
+		"projects": [{
+			"name": "api",
+			"groupName": "team",
+			"provider": "gh",
+			"token": "bot",
+			"branches": [{"name": "main", "default": true}]
+		}]
+	}`))
+	require.NoError(t, err)
+	require.Len(t, cfg.Projects, 1)
+	assert.Equal(t, "team/api", cfg.Projects[0].Key())
+	assert.Nil(t, cfg.Projects[0].Overrides)
+}
+
+func TestDecode_unknownField(t *testing.T) {
+	_, err := Decode(strings.NewReader(`{"projects": [{"name": "api", "gropName": "team"}]}`))
+	assert.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	cfg := Config{
+		Providers: []Provider{{Ref: "gh", Name: "bitbucket", URL: "https://example.com"}},
+		Projects: []Project{
+			{Name: "api", Provider: "gl", Token: "bot"},
+			{Name: "api", Branches: []Branch{{Name: "a", Default: true}, {Name: "b", Default: true}}},
+		},
+	}
+	err := cfg.Validate()
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, ErrInvalidConfig))
+	for _, want := range []string{
+		`providers[0]: invalid name "bitbucket"`,
+		`projects[0]: unknown provider ref "gl"`,
+		`projects[0]: unknown token ref "bot"`,
+		`projects[1]: duplicate project "api"`,
+		`projects[1]: 2 default branches`,
+	} {
+		assert.Contains(t, err.Error(), want)
+	}
+}
@@ -0,0 +1,344 @@
+package wharfconfig
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/request"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/wharfapi"
+)
+
+// Action is an enum of changes that can be made to a project.
+type Action string
+
+const (
+	// ActionNoop means the project already matches the desired state.
+	ActionNoop Action = "no-op"
+	// ActionCreate means the project does not exist and will be created.
+	ActionCreate Action = "create"
+	// ActionUpdate means the project exists but differs from the desired
+	// state, and will be updated.
+	ActionUpdate Action = "update"
+	// ActionDelete means the project exists but is not in the configuration,
+	// and will be deleted. Only planned when PlanOptions.Prune is set.
+	ActionDelete Action = "delete"
+)
+
+// FieldDiff is the difference in a single field between the current and the
+// desired state.
+type FieldDiff struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// ProjectChange is a planned change to a single project.
+type ProjectChange struct {
+	Action Action
+	// ProjectID is zero for projects that are going to be created.
+	ProjectID uint
+	GroupName string
+	Name      string
+	Diffs     []FieldDiff
+
+	project         request.ProjectUpdate
+	overrides       request.ProjectOverridesUpdate
+	branches        []request.Branch
+	updateProject   bool
+	updateOverrides bool
+	updateBranches  bool
+}
+
+// Key returns the project's group name and name.
+func (c ProjectChange) Key() string {
+	return projectKey(c.GroupName, c.Name)
+}
+
+// PlanOptions holds optional settings used when planning.
+type PlanOptions struct {
+	// Prune enables planning deletes of projects that are not in the
+	// configuration. Only projects in groups that the configuration has at
+	// least one project in are considered, so that unrelated projects are
+	// never deleted.
+	Prune bool
+}
+
+// Plan is the set of changes needed to reconcile wharf-api with the desired
+// state. The changes are sorted by project group name and name.
+type Plan struct {
+	Changes []ProjectChange
+}
+
+// HasChanges returns true if the plan contains any creates, updates, or
+// deletes.
+func (p Plan) HasChanges() bool {
+	for _, c := range p.Changes {
+		if c.Action != ActionNoop {
+			return true
+		}
+	}
+	return false
+}
+
+// Count returns the number of changes with the given action.
+func (p Plan) Count(action Action) int {
+	var n int
+	for _, c := range p.Changes {
+		if c.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// NewPlan fetches the current projects from wharf-api and computes the
+// changes needed to reach the desired state of the configuration. Nothing is
+// changed in wharf-api.
+func NewPlan(c *wharfapi.Client, cfg Config, opts PlanOptions) (Plan, error) {
+	if err := cfg.Validate(); err != nil {
+		return Plan{}, err
+	}
+	providerIDs, err := resolveProviders(c, cfg.Providers)
+	if err != nil {
+		return Plan{}, err
+	}
+	tokenIDs, err := resolveTokens(c, cfg.Tokens)
+	if err != nil {
+		return Plan{}, err
+	}
+	projects, err := c.GetProjectListAll(wharfapi.ProjectSearch{})
+	if err != nil {
+		return Plan{}, fmt.Errorf("get project list: %w", err)
+	}
+	current := make(map[string]response.Project, len(projects))
+	for _, p := range projects {
+		key := projectKey(p.GroupName, p.Name)
+		if other, ok := current[key]; ok {
+			return Plan{}, fmt.Errorf("project %q: ambiguous, matches both project IDs %d and %d",
+				key, other.ProjectID, p.ProjectID)
+		}
+		current[key] = p
+	}
+
+	var plan Plan
+	managedGroups := map[string]bool{}
+	for _, desired := range cfg.Projects {
+		managedGroups[desired.GroupName] = true
+		cur, exists := current[desired.Key()]
+		var curOverrides response.ProjectOverrides
+		if exists && desired.Overrides != nil {
+			curOverrides, err = c.GetProjectOverrides(cur.ProjectID)
+			if err != nil {
+				return Plan{}, fmt.Errorf("project %q: get overrides: %w", desired.Key(), err)
+			}
+		}
+		change := planProject(desired, cur, exists, curOverrides,
+			providerIDs[desired.Provider], tokenIDs[desired.Token])
+		plan.Changes = append(plan.Changes, change)
+	}
+	if opts.Prune {
+		desiredKeys := make(map[string]bool, len(cfg.Projects))
+		for _, p := range cfg.Projects {
+			desiredKeys[p.Key()] = true
+		}
+		for key, cur := range current {
+			if desiredKeys[key] || !managedGroups[cur.GroupName] {
+				continue
+			}
+			plan.Changes = append(plan.Changes, ProjectChange{
+				Action:    ActionDelete,
+				ProjectID: cur.ProjectID,
+				GroupName: cur.GroupName,
+				Name:      cur.Name,
+			})
+		}
+	}
+	sort.SliceStable(plan.Changes, func(i, j int) bool {
+		a, b := plan.Changes[i], plan.Changes[j]
+		if a.GroupName != b.GroupName {
+			return a.GroupName < b.GroupName
+		}
+		return a.Name < b.Name
+	})
+	return plan, nil
+}
+
+func planProject(desired Project, cur response.Project, exists bool, curOverrides response.ProjectOverrides, providerID, tokenID uint) ProjectChange {
+	if desired.Provider == "" {
+		providerID = cur.ProviderID
+	}
+	if desired.Token == "" {
+		tokenID = cur.TokenID
+	}
+	change := ProjectChange{
+		Action:    ActionNoop,
+		ProjectID: cur.ProjectID,
+		GroupName: desired.GroupName,
+		Name:      desired.Name,
+		project: request.ProjectUpdate{
+			Name:            desired.Name,
+			GroupName:       desired.GroupName,
+			Description:     desired.Description,
+			AvatarURL:       desired.AvatarURL,
+			TokenID:         tokenID,
+			ProviderID:      providerID,
+			BuildDefinition: desired.BuildDefinition,
+			GitURL:          desired.GitURL,
+		},
+	}
+	if !exists {
+		change.Action = ActionCreate
+		cur = response.Project{}
+	}
+
+	diffs := &change.Diffs
+	n := len(*diffs)
+	diffString(diffs, "description", cur.Description, desired.Description)
+	diffString(diffs, "avatarUrl", cur.AvatarURL, desired.AvatarURL)
+	diffString(diffs, "gitUrl", cur.GitURL, desired.GitURL)
+	diffString(diffs, "buildDefinition", cur.BuildDefinition, desired.BuildDefinition)
+	diffID(diffs, "providerId", cur.ProviderID, providerID)
+	diffID(diffs, "tokenId", cur.TokenID, tokenID)
+	change.updateProject = len(*diffs) > n
+
+	if desired.Overrides != nil {
+		change.overrides = request.ProjectOverridesUpdate{
+			Description: desired.Overrides.Description,
+			AvatarURL:   desired.Overrides.AvatarURL,
+			GitURL:      desired.Overrides.GitURL,
+		}
+		n = len(*diffs)
+		diffString(diffs, "overrides.description", curOverrides.Description, desired.Overrides.Description)
+		diffString(diffs, "overrides.avatarUrl", curOverrides.AvatarURL, desired.Overrides.AvatarURL)
+		diffString(diffs, "overrides.gitUrl", curOverrides.GitURL, desired.Overrides.GitURL)
+		change.updateOverrides = len(*diffs) > n
+	}
+
+	if len(desired.Branches) > 0 {
+		for _, b := range desired.Branches {
+			change.branches = append(change.branches, request.Branch{Name: b.Name, Default: b.Default})
+		}
+		curBranches := make([]request.Branch, 0, len(cur.Branches))
+		for _, b := range cur.Branches {
+			curBranches = append(curBranches, request.Branch{Name: b.Name, Default: b.Default})
+		}
+		n = len(*diffs)
+		diffString(diffs, "branches", formatBranches(curBranches), formatBranches(change.branches))
+		change.updateBranches = len(*diffs) > n
+	}
+
+	if exists && (change.updateProject || change.updateOverrides || change.updateBranches) {
+		change.Action = ActionUpdate
+	}
+	return change
+}
+
+func diffString(diffs *[]FieldDiff, field, oldValue, newValue string) {
+	if oldValue != newValue {
+		*diffs = append(*diffs, FieldDiff{Field: field, Old: oldValue, New: newValue})
+	}
+}
+
+func diffID(diffs *[]FieldDiff, field string, oldValue, newValue uint) {
+	diffString(diffs, field,
+		strconv.FormatUint(uint64(oldValue), 10),
+		strconv.FormatUint(uint64(newValue), 10))
+}
+
+// formatBranches formats the branches as a sorted comma-separated list, where
+// the default branch is suffixed with an asterisk, so that branch lists can be
+// compared regardless of order.
+func formatBranches(branches []request.Branch) string {
+	names := make([]string, 0, len(branches))
+	for _, b := range branches {
+		if b.Default {
+			names = append(names, b.Name+"*")
+		} else {
+			names = append(names, b.Name)
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func resolveProviders(c *wharfapi.Client, providers []Provider) (map[string]uint, error) {
+	ids := make(map[string]uint, len(providers))
+	for _, p := range providers {
+		name := string(p.Name)
+		url := p.URL
+		list, err := c.GetProviderList(wharfapi.ProviderSearch{Name: &name, URL: &url})
+		if err != nil {
+			return nil, fmt.Errorf("provider %q: %w", p.Ref, err)
+		}
+		switch len(list.List) {
+		case 0:
+			return nil, fmt.Errorf("provider %q: no provider found with name %q and URL %q", p.Ref, p.Name, p.URL)
+		case 1:
+			ids[p.Ref] = list.List[0].ProviderID
+		default:
+			return nil, fmt.Errorf("provider %q: %d providers found with name %q and URL %q", p.Ref, len(list.List), p.Name, p.URL)
+		}
+	}
+	return ids, nil
+}
+
+func resolveTokens(c *wharfapi.Client, tokens []Token) (map[string]uint, error) {
+	ids := make(map[string]uint, len(tokens))
+	for _, t := range tokens {
+		userName := t.UserName
+		list, err := c.GetTokenList(wharfapi.TokenSearch{UserName: &userName})
+		if err != nil {
+			return nil, fmt.Errorf("token %q: %w", t.Ref, err)
+		}
+		switch len(list.List) {
+		case 0:
+			return nil, fmt.Errorf("token %q: no token found with user name %q", t.Ref, t.UserName)
+		case 1:
+			ids[t.Ref] = list.List[0].TokenID
+		default:
+			return nil, fmt.Errorf("token %q: %d tokens found with user name %q", t.Ref, len(list.List), t.UserName)
+		}
+	}
+	return ids, nil
+}
+
+// WriteText writes the plan in a human-readable format, listing each change
+// together with its field-level differences. Projects without changes are
+// omitted.
+func (p Plan) WriteText(w io.Writer) error {
+	var err error
+	printf := func(format string, args ...interface{}) {
+		if err == nil {
+			_, err = fmt.Fprintf(w, format, args...)
+		}
+	}
+	for _, c := range p.Changes {
+		switch c.Action {
+		case ActionCreate:
+			printf("+ create %s\n", c.Key())
+		case ActionUpdate:
+			printf("~ update %s (project ID %d)\n", c.Key(), c.ProjectID)
+		case ActionDelete:
+			printf("- delete %s (project ID %d)\n", c.Key(), c.ProjectID)
+		default:
+			continue
+		}
+		for _, d := range c.Diffs {
+			printf("    %s: %s -> %s\n", d.Field, formatValue(d.Old), formatValue(d.New))
+		}
+	}
+	printf("Plan: %d to create, %d to update, %d to delete, %d unchanged.\n",
+		p.Count(ActionCreate), p.Count(ActionUpdate), p.Count(ActionDelete), p.Count(ActionNoop))
+	return err
+}
+
+func formatValue(s string) string {
+	if lines := strings.Count(s, "\n"); lines > 0 {
+		return fmt.Sprintf("(%d lines)", lines+1)
+	}
+	return strconv.Quote(s)
+}
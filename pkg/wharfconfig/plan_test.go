@@ -0,0 +1,148 @@
+package wharfconfig
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/request"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/response"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/wharfapi"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/wharfapitest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestAPI returns a client to a fake wharf-api that has the provider and
+// token referenced by newTestConfig, along with their IDs.
+func newTestAPI(t *testing.T) (c *wharfapi.Client, providerID, tokenID uint) {
+	srv := wharfapitest.NewServer()
+	t.Cleanup(srv.Close)
+	c = srv.Client()
+	token, err := c.CreateToken(request.Token{Token: "secret", UserName: "wharf-bot"})
+	require.NoError(t, err)
+	provider, err := c.CreateProvider(request.Provider{
+		Name: request.ProviderGitLab, URL: "https://gitlab.example.com", TokenID: token.TokenID})
+	require.NoError(t, err)
+	return c, provider.ProviderID, token.TokenID
+}
+
+func createTestProject(t *testing.T, c *wharfapi.Client, p request.Project) uint {
+	project, err := c.CreateProject(p)
+	require.NoError(t, err)
+	return project.ProjectID
+}
+
+func findTestProject(t *testing.T, c *wharfapi.Client, groupName, name string) (response.Project, bool) {
+	projects, err := c.GetProjectListAll(wharfapi.ProjectSearch{GroupName: &groupName, Name: &name})
+	require.NoError(t, err)
+	if len(projects) != 1 {
+		return response.Project{}, false
+	}
+	return projects[0], true
+}
+
+func newTestConfig() Config {
+	return Config{
+		Providers: []Provider{{Ref: "gitlab", Name: request.ProviderGitLab, URL: "https://gitlab.example.com"}},
+		Tokens:    []Token{{Ref: "bot", UserName: "wharf-bot"}},
+		Projects: []Project{
+			{
+				Name: "api", GroupName: "team", Description: "The API",
+				Provider: "gitlab", Token: "bot",
+				Overrides: &Overrides{Description: "Overridden"},
+				Branches:  []Branch{{Name: "main", Default: true}, {Name: "dev"}},
+			},
+			{
+				Name: "web", GroupName: "team", Description: "The web",
+				Provider: "gitlab", Token: "bot",
+			},
+		},
+	}
+}
+
+func TestPlanAndApply(t *testing.T) {
+	c, providerID, tokenID := newTestAPI(t)
+	webID := createTestProject(t, c, request.Project{Name: "web", GroupName: "team",
+		Description: "Old", ProviderID: providerID, TokenID: tokenID})
+	createTestProject(t, c, request.Project{Name: "legacy", GroupName: "team"})
+	createTestProject(t, c, request.Project{Name: "unrelated", GroupName: "other"})
+	cfg := newTestConfig()
+
+	plan, err := NewPlan(c, cfg, PlanOptions{})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 2)
+	assert.Equal(t, ActionCreate, plan.Changes[0].Action)
+	assert.Equal(t, "team/api", plan.Changes[0].Key())
+	assert.Equal(t, ActionUpdate, plan.Changes[1].Action)
+	assert.Equal(t, []FieldDiff{{Field: "description", Old: "Old", New: "The web"}}, plan.Changes[1].Diffs)
+
+	var buf bytes.Buffer
+	require.NoError(t, plan.WriteText(&buf))
+	assert.Contains(t, buf.String(), "+ create team/api\n")
+	assert.Contains(t, buf.String(), `    branches: "" -> "dev, main*"`)
+	assert.Contains(t, buf.String(), "Plan: 1 to create, 1 to update, 0 to delete, 0 unchanged.\n")
+
+	result, err := Apply(c, plan)
+	require.NoError(t, err)
+	assert.Len(t, result.Applied, 2)
+
+	web, err := c.GetProject(webID)
+	require.NoError(t, err)
+	assert.Equal(t, "The web", web.Description)
+	api, ok := findTestProject(t, c, "team", "api")
+	require.True(t, ok, "team/api should be created")
+	assert.Equal(t, providerID, api.ProviderID)
+	assert.Equal(t, tokenID, api.TokenID)
+	overrides, err := c.GetProjectOverrides(api.ProjectID)
+	require.NoError(t, err)
+	assert.Equal(t, "Overridden", overrides.Description)
+	branches, err := c.GetProjectBranchList(api.ProjectID)
+	require.NoError(t, err)
+	assert.Len(t, branches, 2)
+
+	plan, err = NewPlan(c, cfg, PlanOptions{})
+	require.NoError(t, err)
+	assert.False(t, plan.HasChanges(), "plan after apply should only contain no-ops")
+}
+
+func TestPlan_emptyRefsKeepProviderAndToken(t *testing.T) {
+	c, providerID, tokenID := newTestAPI(t)
+	webID := createTestProject(t, c, request.Project{Name: "web", GroupName: "team",
+		Description: "Old", ProviderID: providerID, TokenID: tokenID})
+	cfg := Config{Projects: []Project{
+		{Name: "web", GroupName: "team", Description: "The web"},
+	}}
+
+	plan, err := NewPlan(c, cfg, PlanOptions{})
+	require.NoError(t, err)
+	require.Len(t, plan.Changes, 1)
+	assert.Equal(t, []FieldDiff{{Field: "description", Old: "Old", New: "The web"}}, plan.Changes[0].Diffs)
+
+	_, err = Apply(c, plan)
+	require.NoError(t, err)
+	web, err := c.GetProject(webID)
+	require.NoError(t, err)
+	assert.Equal(t, providerID, web.ProviderID)
+	assert.Equal(t, tokenID, web.TokenID)
+}
+
+func TestPlan_prune(t *testing.T) {
+	c, _, _ := newTestAPI(t)
+	createTestProject(t, c, request.Project{Name: "legacy", GroupName: "team"})
+	createTestProject(t, c, request.Project{Name: "unrelated", GroupName: "other"})
+	cfg := newTestConfig()
+
+	plan, err := NewPlan(c, cfg, PlanOptions{})
+	require.NoError(t, err)
+	assert.Zero(t, plan.Count(ActionDelete))
+
+	plan, err = NewPlan(c, cfg, PlanOptions{Prune: true})
+	require.NoError(t, err)
+	require.Equal(t, 1, plan.Count(ActionDelete))
+	_, err = Apply(c, plan)
+	require.NoError(t, err)
+	_, ok := findTestProject(t, c, "team", "legacy")
+	assert.False(t, ok, "team/legacy should be deleted")
+	_, ok = findTestProject(t, c, "other", "unrelated")
+	assert.True(t, ok, "other/unrelated should be kept")
+}
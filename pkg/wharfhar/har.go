@@ -0,0 +1,140 @@
+// Package wharfhar records HTTP interactions between a wharfapi.Client and
+// the wharf-api as HAR (HTTP Archive) files, and replays them offline, such
+// as in unit tests.
+//
+// The HAR format is specified at http://www.softwareishard.com/blog/har-12-spec/
+//
+// Usage when recording:
+//  rec := wharfhar.NewRecorder(nil)
+//  client := wharfapi.Client{APIURL: url, HTTPClient: &http.Client{Transport: rec}}
+//  // ...use the client...
+//  err := rec.WriteFile("testdata/get-project.har")
+//
+// Usage when replaying:
+//  rep, err := wharfhar.LoadReplayer("testdata/get-project.har")
+//  client := wharfapi.Client{APIURL: url, HTTPClient: &http.Client{Transport: rep}}
+package wharfhar
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+)
+
+// Version is the HAR format version written by the Recorder.
+const Version = "1.2"
+
+// HAR is the root object of a HAR file.
+type HAR struct {
+	Log Log `json:"log"`
+}
+
+// Log holds the recorded entries.
+type Log struct {
+	Version string  `json:"version"`
+	Creator Creator `json:"creator"`
+	Entries []Entry `json:"entries"`
+}
+
+// Creator is the application that wrote the HAR file.
+type Creator struct {
+	Name string `json:"name"`
+	// Version is the version of the application. Left empty by the Recorder,
+	// as the version of this module is not known at runtime.
+	Version string `json:"version"`
+}
+
+// Entry is a single recorded HTTP request and its response.
+type Entry struct {
+	StartedDateTime string   `json:"startedDateTime"`
+	Time            float64  `json:"time"`
+	Request         Request  `json:"request"`
+	Response        Response `json:"response"`
+	Cache           struct{} `json:"cache"`
+	Timings         Timings  `json:"timings"`
+}
+
+// Request is a recorded HTTP request.
+type Request struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	QueryString []NameValue `json:"queryString"`
+	PostData    *PostData   `json:"postData,omitempty"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+// Response is a recorded HTTP response.
+type Response struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HTTPVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	Content     Content     `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+// NameValue is a header or query string parameter.
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Cookie is a recorded cookie. Cookies are never recorded by the Recorder,
+// but the type is needed to read HAR files written by other tools.
+type Cookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// PostData is a recorded request body.
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// Content is a recorded response body. Binary bodies are base64 encoded.
+type Content struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// Timings holds the durations, in milliseconds, of the different phases of
+// a request. Only the total time is known, which is reported as waiting.
+type Timings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// Read decodes a HAR file.
+func Read(r io.Reader) (HAR, error) {
+	var har HAR
+	err := json.NewDecoder(r).Decode(&har)
+	return har, err
+}
+
+// Load reads a HAR file from disk.
+func Load(path string) (HAR, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return HAR{}, err
+	}
+	defer file.Close()
+	return Read(file)
+}
+
+// Write encodes the HAR as indented JSON.
+func (h HAR) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(h)
+}
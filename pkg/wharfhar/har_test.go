@@ -0,0 +1,132 @@
+package wharfhar
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/model/request"
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/wharfapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/project/5":
+			fmt.Fprint(w, `{"projectId":5,"name":"api"}`)
+		case "/api/token":
+			fmt.Fprint(w, `{"tokenId":3,"token":"ghp_secret-value","userName":"bot"}`)
+		case "/api/provider":
+			fmt.Fprint(w, `{"totalCount":0,"list":[]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	rec := NewRecorder(nil)
+	c := wharfapi.Client{
+		APIURL:                 srv.URL,
+		AuthHeader:             "Bearer my-auth-token",
+		DisableOutdatedLogging: true,
+		HTTPClient:             &http.Client{Transport: rec},
+	}
+	_, err := c.GetProject(5)
+	require.NoError(t, err)
+	_, err = c.CreateToken(request.Token{Token: "ghp_new-secret", UserName: "bot"})
+	require.NoError(t, err)
+	name, url := "github", "https://github.com"
+	_, err = c.GetProviderList(wharfapi.ProviderSearch{Name: &name, URL: &url})
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	require.NoError(t, rec.HAR().Write(&buf))
+	recorded := buf.String()
+	assert.NotContains(t, recorded, "my-auth-token")
+	assert.NotContains(t, recorded, "secret-value")
+	assert.NotContains(t, recorded, "new-secret")
+	assert.Contains(t, recorded, "Bearer *REDACTED*")
+
+	har, err := Read(&buf)
+	require.NoError(t, err)
+	require.Len(t, har.Log.Entries, 3)
+
+	srv.Close()
+	rep := NewReplayer(har)
+	c.HTTPClient = &http.Client{Transport: rep}
+
+	project, err := c.GetProject(5)
+	require.NoError(t, err)
+	assert.Equal(t, "api", project.Name)
+
+	token, err := c.CreateToken(request.Token{Token: "ghp_new-secret", UserName: "bot"})
+	require.NoError(t, err)
+	assert.Equal(t, "bot", token.UserName)
+	assert.Equal(t, "*REDACTED*", token.Token.Reveal())
+
+	// Query parameters in a different order should still match.
+	_, err = c.GetProviderList(wharfapi.ProviderSearch{URL: &url, Name: &name})
+	require.NoError(t, err)
+
+	_, err = c.GetProject(6)
+	var unmatched *UnmatchedRequestError
+	require.True(t, errors.As(err, &unmatched), "got: %v", err)
+	assert.Equal(t, http.MethodGet, unmatched.Method)
+}
+
+func TestRecorder_redactsFormBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+		fmt.Fprint(w, "access_token=secret-response&expires_in=3600")
+	}))
+	defer srv.Close()
+
+	rec := NewRecorder(nil)
+	client := &http.Client{Transport: rec}
+	resp, err := client.PostForm(srv.URL, url.Values{
+		"username": {"bot"},
+		"password": {"secret-request"},
+	})
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	har := rec.HAR()
+	require.Len(t, har.Log.Entries, 1)
+	entry := har.Log.Entries[0]
+	require.NotNil(t, entry.Request.PostData)
+	assert.Equal(t, "password=*REDACTED*&username=bot", entry.Request.PostData.Text)
+	assert.Equal(t, "access_token=*REDACTED*&expires_in=3600", entry.Response.Content.Text)
+}
+
+func TestReplayer_servesInRecordedOrder(t *testing.T) {
+	entry := func(body string) Entry {
+		return Entry{
+			Request: Request{Method: http.MethodGet, URL: "http://wharf/api/ping?b=2&a=1"},
+			Response: Response{
+				Status:  http.StatusOK,
+				Content: Content{Text: body},
+			},
+		}
+	}
+	rep := NewReplayer(HAR{Log: Log{Entries: []Entry{entry("first"), entry("second")}}})
+	client := &http.Client{Transport: rep}
+
+	var bodies []string
+	for i := 0; i < 3; i++ {
+		resp, err := client.Get("http://wharf/api/ping?a=1&b=2")
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		_, err = buf.ReadFrom(resp.Body)
+		require.NoError(t, err)
+		resp.Body.Close()
+		bodies = append(bodies, buf.String())
+	}
+	assert.Equal(t, []string{"first", "second", "second"}, bodies)
+}
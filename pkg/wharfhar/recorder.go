@@ -0,0 +1,237 @@
+package wharfhar
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/wharfapi"
+)
+
+// Recorder is an http.RoundTripper that records all requests and responses
+// sent through it, with sensitive values redacted, so they can be written as
+// a HAR file.
+//
+// Safe for concurrent use.
+type Recorder struct {
+	// Transport sends the actual requests. Uses http.DefaultTransport if nil.
+	Transport http.RoundTripper
+	// Redactor removes sensitive values, such as the Authorization header
+	// and tokens, before they are recorded. Uses wharfapi.DefaultRedactor if
+	// nil.
+	Redactor *wharfapi.Redactor
+
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// NewRecorder creates a new Recorder that sends its requests using the given
+// transport, or http.DefaultTransport if nil.
+func NewRecorder(transport http.RoundTripper) *Recorder {
+	return &Recorder{Transport: transport}
+}
+
+// RoundTrip sends the request using the underlying transport, and records
+// the request and response. Implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	start := time.Now()
+	resp, err := r.transport().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+	elapsed := float64(time.Since(start)) / float64(time.Millisecond)
+
+	entry := Entry{
+		StartedDateTime: start.Format(time.RFC3339Nano),
+		Time:            elapsed,
+		Request:         r.newRequest(req, reqBody),
+		Response:        r.newResponse(resp, respBody),
+		Timings:         Timings{Send: -1, Wait: elapsed, Receive: -1},
+	}
+	r.mu.Lock()
+	r.entries = append(r.entries, entry)
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// HAR returns all recorded entries as a HAR.
+func (r *Recorder) HAR() HAR {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entries := make([]Entry, len(r.entries))
+	copy(entries, r.entries)
+	return HAR{Log: Log{
+		Version: Version,
+		Creator: Creator{Name: "wharf-api-client-go/wharfhar"},
+		Entries: entries,
+	}}
+}
+
+// WriteFile writes all recorded entries as a HAR file.
+func (r *Recorder) WriteFile(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := r.HAR().Write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func (r *Recorder) transport() http.RoundTripper {
+	if r.Transport != nil {
+		return r.Transport
+	}
+	return http.DefaultTransport
+}
+
+func (r *Recorder) redactor() *wharfapi.Redactor {
+	return resolveRedactor(r.Redactor)
+}
+
+func (r *Recorder) newRequest(req *http.Request, body []byte) Request {
+	redactor := r.redactor()
+	redactedURL := redactor.URL(req.URL.String())
+	harReq := Request{
+		Method:      req.Method,
+		URL:         redactedURL,
+		HTTPVersion: httpVersion(req.Proto),
+		Cookies:     []Cookie{},
+		Headers:     nameValues(redactor.Header(req.Header)),
+		QueryString: []NameValue{},
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+	if u, err := url.Parse(redactedURL); err == nil {
+		harReq.QueryString = nameValues(u.Query())
+	}
+	if body != nil {
+		contentType := req.Header.Get("Content-Type")
+		harReq.PostData = &PostData{
+			MimeType: contentType,
+			Text:     redactedBodyText(redactor, contentType, body),
+		}
+	}
+	return harReq
+}
+
+func (r *Recorder) newResponse(resp *http.Response, body []byte) Response {
+	redactor := r.redactor()
+	contentType := resp.Header.Get("Content-Type")
+	harResp := Response{
+		Status:      resp.StatusCode,
+		StatusText:  http.StatusText(resp.StatusCode),
+		HTTPVersion: httpVersion(resp.Proto),
+		Cookies:     []Cookie{},
+		Headers:     nameValues(redactor.Header(resp.Header)),
+		Content: Content{
+			Size:     len(body),
+			MimeType: contentType,
+		},
+		RedirectURL: resp.Header.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+	if _, text, ok := cutStatus(resp.Status); ok {
+		// Prefers the status text sent by the server.
+		harResp.StatusText = text
+	}
+	if isText(contentType, body) {
+		harResp.Content.Text = redactedBodyText(redactor, contentType, body)
+	} else {
+		harResp.Content.Text = base64.StdEncoding.EncodeToString(body)
+		harResp.Content.Encoding = "base64"
+	}
+	return harResp
+}
+
+// redactedBodyText redacts JSON and URL-encoded form bodies, and leaves other
+// textual bodies as-is. Binary bodies, such as multipart uploads, are not
+// recorded.
+func redactedBodyText(redactor *wharfapi.Redactor, contentType string, body []byte) string {
+	if !isText(contentType, body) {
+		return ""
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if mediaType == "application/x-www-form-urlencoded" {
+		return redactor.Query(string(body))
+	}
+	if mediaType == "" || mediaType == "application/json" || strings.HasSuffix(mediaType, "+json") {
+		return string(redactor.JSON(body))
+	}
+	return string(body)
+}
+
+func isText(contentType string, body []byte) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	if strings.HasPrefix(mediaType, "multipart/") {
+		return false
+	}
+	return utf8.Valid(body)
+}
+
+func nameValues(values map[string][]string) []NameValue {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	list := []NameValue{}
+	for _, name := range names {
+		for _, v := range values[name] {
+			list = append(list, NameValue{Name: name, Value: v})
+		}
+	}
+	return list
+}
+
+func httpVersion(proto string) string {
+	if proto == "" {
+		return "HTTP/1.1"
+	}
+	return proto
+}
+
+func cutStatus(status string) (code, text string, ok bool) {
+	i := strings.IndexByte(status, ' ')
+	if i == -1 {
+		return status, "", false
+	}
+	return status[:i], status[i+1:], true
+}
+
+func resolveRedactor(redactor *wharfapi.Redactor) *wharfapi.Redactor {
+	if redactor != nil {
+		return redactor
+	}
+	defaultRedactor := wharfapi.DefaultRedactor()
+	return &defaultRedactor
+}
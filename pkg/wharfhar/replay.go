@@ -0,0 +1,157 @@
+package wharfhar
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/iver-wharf/wharf-api-client-go/v2/pkg/wharfapi"
+)
+
+// UnmatchedRequestError is returned by the Replayer when no recorded entry
+// matches a request.
+type UnmatchedRequestError struct {
+	Method string
+	// URL is the request URL, with sensitive values redacted.
+	URL string
+}
+
+// Error implements the error interface.
+func (e *UnmatchedRequestError) Error() string {
+	return fmt.Sprintf("no recorded response for request: %s %s", e.Method, e.URL)
+}
+
+// Replayer is an http.RoundTripper that serves recorded responses from a HAR,
+// without sending any requests.
+//
+// Requests are matched on their method, path, and query, where the order of
+// the query parameters does not matter. Sensitive query parameters are
+// redacted before matching, the same way as when recording. If multiple
+// entries match, then they are served in the order they were recorded, and
+// the last one is repeated once they have all been served.
+//
+// Safe for concurrent use.
+type Replayer struct {
+	// Redactor must match the redactor used when recording. Uses
+	// wharfapi.DefaultRedactor if nil.
+	Redactor *wharfapi.Redactor
+
+	mu      sync.Mutex
+	entries []Entry
+	served  []bool
+}
+
+// NewReplayer creates a new Replayer serving the entries of the HAR.
+func NewReplayer(har HAR) *Replayer {
+	return &Replayer{
+		entries: har.Log.Entries,
+		served:  make([]bool, len(har.Log.Entries)),
+	}
+}
+
+// LoadReplayer creates a new Replayer serving the entries of a HAR file.
+func LoadReplayer(path string) (*Replayer, error) {
+	har, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayer(har), nil
+}
+
+// RoundTrip serves the recorded response that matches the request, or
+// returns an *UnmatchedRequestError. Implements http.RoundTripper.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	redactor := resolveRedactor(r.Redactor)
+	key := newMatchKey(req.Method, redactor.URL(req.URL.String()))
+	entry, ok := r.nextMatch(key, redactor)
+	if !ok {
+		return nil, &UnmatchedRequestError{
+			Method: req.Method,
+			URL:    redactor.URL(req.URL.String()),
+		}
+	}
+	return newResponse(req, entry.Response)
+}
+
+func (r *Replayer) nextMatch(key matchKey, redactor *wharfapi.Redactor) (Entry, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	last := -1
+	for i, e := range r.entries {
+		if newMatchKey(e.Request.Method, redactor.URL(e.Request.URL)) != key {
+			continue
+		}
+		if !r.served[i] {
+			r.served[i] = true
+			return e, true
+		}
+		last = i
+	}
+	if last == -1 {
+		return Entry{}, false
+	}
+	return r.entries[last], true
+}
+
+type matchKey struct {
+	method string
+	path   string
+	query  string
+}
+
+func newMatchKey(method, rawURL string) matchKey {
+	key := matchKey{method: strings.ToUpper(method)}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		key.path = rawURL
+		return key
+	}
+	key.path = u.Path
+	key.query = normalizeQuery(u.Query())
+	return key
+}
+
+// normalizeQuery encodes the query with sorted keys and values.
+func normalizeQuery(q url.Values) string {
+	for _, values := range q {
+		sort.Strings(values)
+	}
+	return q.Encode()
+}
+
+func newResponse(req *http.Request, r Response) (*http.Response, error) {
+	body := []byte(r.Content.Text)
+	if r.Content.Encoding == "base64" {
+		var err error
+		body, err = base64.StdEncoding.DecodeString(r.Content.Text)
+		if err != nil {
+			return nil, fmt.Errorf("decode recorded response body: %w", err)
+		}
+	}
+	header := http.Header{}
+	for _, h := range r.Headers {
+		header.Add(h.Name, h.Value)
+	}
+	// The body may have been changed by the redaction.
+	header.Del("Content-Length")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, r.StatusText),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}